@@ -7,32 +7,366 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/spf13/viper"
 )
 
 type (
+	GitStatus struct {
+		Dirty     bool
+		Modified  []string
+		Staged    []string
+		Untracked []string
+	} // GitStatus - Status of the working tree of a repository.
+
+	GitRef struct {
+		Name     string
+		Short    string
+		Type     string
+		Hash     string
+		Repo     string
+		Shallow  bool
+		Boundary []string
+		Worktree GitStatus
+	} // GitRef - Ref processed by a git handler.
+
+	GitMessage struct {
+		Type     string
+		Scope    string
+		Breaking bool
+		Subject  string
+		Body     string
+		Trailers map[string][]string
+	} // GitMessage - Parsed commit message; Conventional Commits header and trailers.
+
+	GitDiffLine struct {
+		Type      string
+		Text      string
+		OldNumber int
+		NewNumber int
+	} // GitDiffLine - Line of a diff hunk.
+
+	GitDiffHunk struct {
+		OldStart int
+		OldLines int
+		NewStart int
+		NewLines int
+		Lines    []GitDiffLine
+	} // GitDiffHunk - Hunk of a file diff.
+
+	GitFileDiff struct {
+		Type    string
+		OldPath string
+		NewPath string
+		Binary  bool
+		Hunks   []GitDiffHunk
+		Patch   string
+	} // GitFileDiff - Changes to a file in a commit.
+
+	GitSignature struct {
+		Signed bool
+		Type   string
+		Valid  bool
+		Signer string
+		KeyID  string
+		Error  string
+	} // GitSignature - Verification of the signature of a commit or tag.
+
+	GitReference struct {
+		Name   string
+		Text   string
+		Key    string
+		Number string
+		URL    string
+	} // GitReference - Issue or ticket reference of a commit message.
+
 	GitLogEntry struct {
-		Commit *object.Commit
-		Stats  object.FileStats
+		Commit      *object.Commit
+		Author      GitAuthor
+		Committer   GitAuthor
+		Stats       object.FileStats
+		Parsed      GitMessage
+		Ref         GitRef
+		Diff        []GitFileDiff
+		Signature   GitSignature
+		WorkingCopy bool
+		Boundary    bool
+		References  []GitReference
 	} // GitLogEntry - Individual git log entry and changed files.
 
 	GitAll struct {
-		Commits []GitLogEntry
-		Head    GitLogEntry
-	} // GitAll - Entire Git log.
+		Commits      []GitLogEntry
+		Head         GitLogEntry
+		Groups       map[string][]GitLogEntry
+		Ref          GitRef
+		Page         int
+		TotalPages   int
+		PrevPage     int
+		NextPage     int
+		TotalCommits int
+	} // GitAll - Entire Git log, or a page of it.
+
+	GitAuthor struct {
+		Name  string
+		Email string
+	} // GitAuthor - Canonical identity of an author or committer.
+
+	GitFile struct {
+		Path      string
+		Commits   []GitLogEntry
+		FirstDate time.Time
+		LastDate  time.Time
+		Authors   []GitAuthor
+		Count     int
+		OldPaths  []string
+		Ref       GitRef
+	} // GitFile - Git log of an individual file.
+
+	GitTag struct {
+		Name      string
+		Version   string
+		Annotated bool
+		Tagger    object.Signature
+		Signature GitSignature
+		Message   string
+		Target    GitLogEntry
+		Commits   []GitLogEntry
+		Groups    map[string][]GitLogEntry
+		Previous  string
+		Ref       GitRef
+	} // GitTag - Tag and the git log since the previous tag.
+
+	GitContributor struct {
+		Name      string
+		Email     string
+		Commits   int
+		FirstDate time.Time
+		LastDate  time.Time
+		Additions int
+		Deletions int
+		Files     []string
+	} // GitContributor - Aggregated statistics of an author.
+
+	GitContributors struct {
+		Authors []GitContributor
+		Commits int
+		Ref     GitRef
+	} // GitContributors - Aggregated statistics of all authors.
+
+	GitActivityBucket struct {
+		Key       string
+		Start     time.Time
+		Group     string
+		Commits   int
+		Additions int
+		Deletions int
+		Authors   []GitAuthor
+	} // GitActivityBucket - Activity of a period, and of a group if grouped.
+
+	GitActivity struct {
+		Period  string
+		GroupBy string
+		Groups  []string
+		Buckets []GitActivityBucket
+		Commits int
+		Ref     GitRef
+	} // GitActivity - Activity of the git log, by period.
+
+	GitInventoryGroup struct {
+		Name   string
+		Files  int
+		Lines  int
+		Size   int64
+		Binary int
+	} // GitInventoryGroup - Files of an extension or language.
+
+	GitInventoryFile struct {
+		Path   string
+		Group  string
+		Size   int64
+		Lines  int
+		Binary bool
+		LFS    bool
+	} // GitInventoryFile - File of an inventory.
+
+	GitInventoryDelta struct {
+		Files  int
+		Lines  int
+		Size   int64
+		Text   int
+		Binary int
+		Groups []GitInventoryGroup
+	} // GitInventoryDelta - Change of an inventory since the compared ref.
+
+	GitInventory struct {
+		GroupBy string
+		Hash    string
+		Files   int
+		Lines   int
+		Size    int64
+		Text    int
+		Binary  int
+		Groups  []GitInventoryGroup
+		Largest []GitInventoryFile
+		Base    *GitInventory
+		Delta   *GitInventoryDelta
+		Ref     GitRef
+	} // GitInventory - Inventory of the files of a commit.
+
+	GitOwnersSection struct {
+		Name      string
+		Optional  bool
+		Approvals int
+		Pattern   string
+		Line      int
+		Owners    []string
+	} // GitOwnersSection - CODEOWNERS rule matching a file, in a section of the file.
+
+	GitOwnersEntry struct {
+		Path     string
+		Owners   []string
+		Sections []GitOwnersSection
+	} // GitOwnersEntry - Owners of a file.
+
+	GitOwners struct {
+		File    string
+		Files   []GitOwnersEntry
+		Owners  map[string][]string
+		Unowned []string
+		Ref     GitRef
+	} // GitOwners - Owners of the files, per the CODEOWNERS file.
+
+	GitBlameAuthor struct {
+		Name  string
+		Email string
+		Lines int
+		Share float64
+	} // GitBlameAuthor - Lines of a file last changed by an author.
+
+	GitBlameLine struct {
+		Number int
+		Text   string
+		Name   string
+		Email  string
+		Date   time.Time
+		Hash   string
+	} // GitBlameLine - Last change of a line of a file.
+
+	GitBlame struct {
+		Path       string
+		Lines      int
+		Authors    []GitBlameAuthor
+		LastChange GitBlameLine
+		Ref        GitRef
+	} // GitBlame - Line-level authorship of a file.
+
+	GitLFSPointer struct {
+		OID  string
+		Size int64
+	} // GitLFSPointer - Git LFS pointer of a file.
+
+	GitTreeEntry struct {
+		Path string
+		Name string
+		Size int64
+		Hash string
+		LFS  *GitLFSPointer
+	} // GitTreeEntry - File of a git tree.
+
+	GitGraphNode struct {
+		Hash     string
+		Short    string
+		Lane     int
+		Parents  []string
+		Subject  string
+		Author   GitAuthor
+		Date     time.Time
+		Branches []string
+		Tags     []string
+		Merge    bool
+	} // GitGraphNode - Commit of the commit graph.
+
+	GitGraphEdge struct {
+		From  string
+		To    string
+		Merge bool
+	} // GitGraphEdge - Edge of the commit graph; from a commit to its parent.
+
+	GitGraphLane struct {
+		Index int
+		Name  string
+		Head  string
+		Fork  string
+	} // GitGraphLane - Lane of the commit graph; a first-parent chain of commits.
+
+	GitGraph struct {
+		Nodes   []GitGraphNode
+		Edges   []GitGraphEdge
+		Lanes   []GitGraphLane
+		Merges  []string
+		Mermaid string
+		Ref     GitRef
+	} // GitGraph - Commit graph of the git log.
 
 	GitProcessor struct {
 		Mode     string `mapstructure:"mode"`
 		File     string `mapstructure:"file"`
 		Template string `mapstructure:"template"`
 		Script   string `mapstructure:"script"`
+		Path     string `mapstructure:"path"`
+		Pattern  string `mapstructure:"pattern"`
+
+		Since          string   `mapstructure:"since"`
+		Until          string   `mapstructure:"until"`
+		Paths          []string `mapstructure:"paths"`
+		Authors        []string `mapstructure:"authors"`
+		ExcludeAuthors []string `mapstructure:"exclude_authors"`
+		MaxCount       int      `mapstructure:"max_count"`
+		FirstParent    bool     `mapstructure:"first_parent"`
+		NoMerges       bool     `mapstructure:"no_merges"`
+		OnlyMerges     bool     `mapstructure:"only_merges"`
+
+		Diff        bool `mapstructure:"diff"`
+		WorkingCopy bool `mapstructure:"working_copy"`
+		Follow      bool `mapstructure:"follow"`
+
+		Period  string `mapstructure:"period"`
+		GroupBy string `mapstructure:"group_by"`
+
+		PageSize int `mapstructure:"page_size"`
+
+		Largest int    `mapstructure:"largest"`
+		Compare string `mapstructure:"compare"`
 	} // GitProcessor - Configuration structure for processing git log entries.
 
+	GitReferencePattern struct {
+		Name    string `mapstructure:"name"`
+		Pattern string `mapstructure:"pattern"`
+		URL     string `mapstructure:"url"`
+	} // GitReferencePattern - Pattern and URL template of issue or ticket references.
+
+	GitRepo struct {
+		Path  string `mapstructure:"path"`
+		Label string `mapstructure:"label"`
+		Ref   string `mapstructure:"ref"`
+	} // GitRepo - Repository of a git handler merging several repositories.
+
 	Git struct {
-		Path       string         `mapstructure:"path"`
-		Processors []GitProcessor `mapstructure:"processors"`
+		Path       string                `mapstructure:"path"`
+		Repos      []GitRepo             `mapstructure:"repos"`
+		Ref        string                `mapstructure:"ref"`
+		Refs       []string              `mapstructure:"refs"`
+		Mailmap    string                `mapstructure:"mailmap"`
+		Keyring    string                `mapstructure:"keyring"`
+		Worktree   bool                  `mapstructure:"worktree"`
+		Shallow    string                `mapstructure:"shallow"`
+		References []GitReferencePattern `mapstructure:"references"`
+		Processors []GitProcessor        `mapstructure:"processors"`
 	} // Git - Configuration for handling git log entries.
 
 	ExecProcessor struct {
@@ -43,9 +377,25 @@ type (
 		Mode    string `mapstructure:"mode"`
 	} // ExecProcessor - Configuration structure for a single exec.
 
+	ImportRewrite struct {
+		From string `mapstructure:"from"`
+		To   string `mapstructure:"to"`
+	} // ImportRewrite - Rewrite of the paths of imported files.
+
+	Import struct {
+		Path       string          `mapstructure:"path"`
+		Ref        string          `mapstructure:"ref"`
+		Paths      []string        `mapstructure:"paths"`
+		Rewrites   []ImportRewrite `mapstructure:"rewrites"`
+		Target     string          `mapstructure:"target"`
+		Provenance string          `mapstructure:"provenance"`
+	} // Import - Configuration for importing files from another git repository.
+
 	Configs struct {
-		Gits  []Git           `mapstructure:"git,flow"`
-		Execs []ExecProcessor `mapstructure:"exec,flow"`
+		State   string          `mapstructure:"state"`
+		Imports []Import        `mapstructure:"import,flow"`
+		Gits    []Git           `mapstructure:"git,flow"`
+		Execs   []ExecProcessor `mapstructure:"exec,flow"`
 	} // Configs - Array of processor configs.
 )
 
@@ -142,8 +492,55 @@ func checkConfig(configs *Configs) error {
 		}
 	}
 
+	Debug("%s: checking imports", funcName)
+	for i := range configs.Imports {
+		Debug("%s: import %d", funcName, i)
+		if len(configs.Imports[i].Target) == 0 {
+			Debug("%s: import %d: config error; target not defined", funcName, i)
+			return fmt.Errorf("%s: import %d: config error; target not defined", funcName, i)
+		}
+		for _, rewrite := range configs.Imports[i].Rewrites {
+			if _, err := regexp.Compile(rewrite.From); err != nil {
+				Debug("%s: import %d: config error; invalid rewrite pattern: %s", funcName, i, err.Error())
+				return fmt.Errorf("%s: import %d: config error; invalid rewrite pattern: %s", funcName, i, err.Error())
+			}
+		}
+		if provenance := configs.Imports[i].Provenance; len(provenance) > 0 {
+			switch ext := strings.ToLower(filepath.Ext(provenance)); ext {
+			case ".json", ".yaml", ".yml", ".toml":
+			default:
+				Debug("%s: import %d: config error; invalid provenance format: %s", funcName, i, ext)
+				return fmt.Errorf("%s: import %d: config error; invalid provenance format: %s; should be json/yaml/toml", funcName, i, ext)
+			}
+		}
+	}
+
 	Debug("%s: checking gits", funcName)
 	for j := range configs.Gits {
+		if (len(configs.Gits[j].Ref) > 0) && (len(configs.Gits[j].Refs) > 0) {
+			Debug("%s: git %d: config conflict; both ref and refs defined", funcName, j)
+			return fmt.Errorf("%s: git %d: config conflict; both ref and refs defined", funcName, j)
+		}
+
+		if (len(configs.Gits[j].Repos) > 0) && ((len(configs.Gits[j].Path) > 0) || (len(configs.Gits[j].Refs) > 0)) {
+			Debug("%s: git %d: config conflict; repos defined with path or refs", funcName, j)
+			return fmt.Errorf("%s: git %d: config conflict; repos defined with path or refs", funcName, j)
+		}
+
+		switch shallow := strings.ToLower(configs.Gits[j].Shallow); shallow {
+		case "", "error", "warn", "allow":
+		default:
+			Debug("%s: git %d: config error; invalid shallow policy: %s", funcName, j, shallow)
+			return fmt.Errorf("%s: git %d: config error; invalid shallow policy: %s; should be error/warn/allow", funcName, j, shallow)
+		}
+
+		for _, reference := range configs.Gits[j].References {
+			if _, err := regexp.Compile(reference.Pattern); err != nil {
+				Debug("%s: git %d: config error; invalid reference pattern: %s: %s", funcName, j, reference.Name, err.Error())
+				return fmt.Errorf("%s: git %d: config error; invalid reference pattern: %s: %s", funcName, j, reference.Name, err.Error())
+			}
+		}
+
 		Debug("%s: git %d: checking processors", funcName, j)
 		for k := range configs.Gits[j].Processors {
 			Debug("%s: git %d: processor %d", funcName, j, k)
@@ -151,6 +548,71 @@ func checkConfig(configs *Configs) error {
 				Debug("%s: git %d: processor: %d: config conflict; both template and script defined", funcName, j, k)
 				return fmt.Errorf("%s: git %d: processor: %d: config conflict; both template and script defined", funcName, j, k)
 			}
+			switch mode := strings.ToLower(configs.Gits[j].Processors[k].Mode); mode {
+			case "file", "blame", "owners":
+				if len(configs.Gits[j].Processors[k].Pattern) == 0 {
+					Debug("%s: git %d: processor: %d: config error; %s mode requires a pattern", funcName, j, k, mode)
+					return fmt.Errorf("%s: git %d: processor: %d: config error; %s mode requires a pattern", funcName, j, k, mode)
+				}
+			}
+			if configs.Gits[j].Processors[k].WorkingCopy && (strings.ToLower(configs.Gits[j].Processors[k].Mode) != "file") {
+				Debug("%s: git %d: processor: %d: config error; working_copy requires file mode", funcName, j, k)
+				return fmt.Errorf("%s: git %d: processor: %d: config error; working_copy requires file mode", funcName, j, k)
+			}
+			if configs.Gits[j].Processors[k].Follow && (strings.ToLower(configs.Gits[j].Processors[k].Mode) != "file") {
+				Debug("%s: git %d: processor: %d: config error; follow requires file mode", funcName, j, k)
+				return fmt.Errorf("%s: git %d: processor: %d: config error; follow requires file mode", funcName, j, k)
+			}
+			if configs.Gits[j].Processors[k].PageSize < 0 {
+				Debug("%s: git %d: processor: %d: config error; invalid page_size: %d", funcName, j, k, configs.Gits[j].Processors[k].PageSize)
+				return fmt.Errorf("%s: git %d: processor: %d: config error; invalid page_size: %d", funcName, j, k, configs.Gits[j].Processors[k].PageSize)
+			}
+			if (configs.Gits[j].Processors[k].PageSize > 0) && (strings.ToLower(configs.Gits[j].Processors[k].Mode) != "all") {
+				Debug("%s: git %d: processor: %d: config error; page_size requires all mode", funcName, j, k)
+				return fmt.Errorf("%s: git %d: processor: %d: config error; page_size requires all mode", funcName, j, k)
+			}
+			switch period := strings.ToLower(configs.Gits[j].Processors[k].Period); period {
+			case "", "day", "week", "month", "year":
+			default:
+				Debug("%s: git %d: processor: %d: config error; invalid period: %s", funcName, j, k, period)
+				return fmt.Errorf("%s: git %d: processor: %d: config error; invalid period: %s; should be day/week/month/year", funcName, j, k, period)
+			}
+			switch groupBy := strings.ToLower(configs.Gits[j].Processors[k].GroupBy); strings.ToLower(configs.Gits[j].Processors[k].Mode) {
+			case "inventory":
+				switch groupBy {
+				case "", "extension", "language":
+				default:
+					Debug("%s: git %d: processor: %d: config error; invalid group_by: %s", funcName, j, k, groupBy)
+					return fmt.Errorf("%s: git %d: processor: %d: config error; invalid group_by: %s; should be extension/language", funcName, j, k, groupBy)
+				}
+			default:
+				switch groupBy {
+				case "", "author", "directory":
+				default:
+					Debug("%s: git %d: processor: %d: config error; invalid group_by: %s", funcName, j, k, groupBy)
+					return fmt.Errorf("%s: git %d: processor: %d: config error; invalid group_by: %s; should be author/directory", funcName, j, k, groupBy)
+				}
+			}
+			if configs.Gits[j].Processors[k].Largest < 0 {
+				Debug("%s: git %d: processor: %d: config error; invalid largest: %d", funcName, j, k, configs.Gits[j].Processors[k].Largest)
+				return fmt.Errorf("%s: git %d: processor: %d: config error; invalid largest: %d", funcName, j, k, configs.Gits[j].Processors[k].Largest)
+			}
+			if configs.Gits[j].Processors[k].NoMerges && configs.Gits[j].Processors[k].OnlyMerges {
+				Debug("%s: git %d: processor: %d: config conflict; both no_merges and only_merges defined", funcName, j, k)
+				return fmt.Errorf("%s: git %d: processor: %d: config conflict; both no_merges and only_merges defined", funcName, j, k)
+			}
+			for _, value := range []string{configs.Gits[j].Processors[k].Since, configs.Gits[j].Processors[k].Until} {
+				if _, err := ParseTime(value); err != nil {
+					Debug("%s: git %d: processor: %d: config error; %s", funcName, j, k, err.Error())
+					return fmt.Errorf("%s: git %d: processor: %d: config error; %s", funcName, j, k, err.Error())
+				}
+			}
+			for _, value := range append(configs.Gits[j].Processors[k].Authors, configs.Gits[j].Processors[k].ExcludeAuthors...) {
+				if _, err := regexp.Compile(value); err != nil {
+					Debug("%s: git %d: processor: %d: config error; invalid author pattern: %s", funcName, j, k, err.Error())
+					return fmt.Errorf("%s: git %d: processor: %d: config error; invalid author pattern: %s", funcName, j, k, err.Error())
+				}
+			}
 		}
 	}
 
@@ -158,6 +620,54 @@ func checkConfig(configs *Configs) error {
 	return nil
 }
 
+// ParseTime parses a configured date/time; RFC 3339, a plain `YYYY-MM-DD` date, or a
+// relative `<n> <unit>s ago` with units of second, minute, hour, day, week, month or year.
+// An empty value returns nil.
+func ParseTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return &t, nil
+	}
+
+	// Relative times; e.g. `1 year ago` or `2 weeks ago`.
+	fields := strings.Fields(strings.ToLower(value))
+	if len(fields) == 3 && fields[2] == "ago" {
+		n, err := strconv.Atoi(fields[0])
+		if err == nil {
+			now := time.Now()
+			var t time.Time
+			switch strings.TrimSuffix(fields[1], "s") {
+			case "second":
+				t = now.Add(-time.Duration(n) * time.Second)
+			case "minute":
+				t = now.Add(-time.Duration(n) * time.Minute)
+			case "hour":
+				t = now.Add(-time.Duration(n) * time.Hour)
+			case "day":
+				t = now.AddDate(0, 0, -n)
+			case "week":
+				t = now.AddDate(0, 0, -7*n)
+			case "month":
+				t = now.AddDate(0, -n, 0)
+			case "year":
+				t = now.AddDate(-n, 0, 0)
+			default:
+				return nil, fmt.Errorf("invalid time unit: %s", value)
+			}
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid time: %s", value)
+}
+
 // WalkMatch walks the tree and look for files matching the provided pattern.
 func WalkMatch(root, pattern string) ([]string, error) {
 	funcName := "cmn.WalkMatch"
@@ -31,9 +31,16 @@ func init() {
 func run(cmd *cobra.Command, args []string) error {
 	cmn.Debug("run: begin")
 
+	// Run the import processors; first, so the other processors see the imported files.
+	cmn.Debug("run: running import processors")
+	err := processors.Imports(cmn.Config)
+	if err != nil {
+		return err
+	}
+
 	// Run the git processors.
 	cmn.Debug("run: running git processors")
-	err := processors.Gits(cmn.Config)
+	err = processors.Gits(cmn.Config)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,140 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// hunkHeader matches the header of a unified diff hunk; `@@ -1,2 +1,3 @@`.
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// atoi converts a matched number; an empty match, as with an omitted hunk length, is 1.
+func atoi(value string) int {
+	if value == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(value)
+	return n
+}
+
+// parseHunks parses the hunks of a single file unified patch.
+func parseHunks(patch string) []cmn.GitDiffHunk {
+	var hunks []cmn.GitDiffHunk
+	var oldNumber, newNumber int
+	for _, line := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n") {
+		if match := hunkHeader.FindStringSubmatch(line); match != nil {
+			hunks = append(hunks, cmn.GitDiffHunk{
+				OldStart: atoi(match[1]),
+				OldLines: atoi(match[2]),
+				NewStart: atoi(match[3]),
+				NewLines: atoi(match[4]),
+			})
+			oldNumber, newNumber = atoi(match[1]), atoi(match[3])
+			continue
+		}
+		// Skip the file headers before the first hunk, and the no newline markers.
+		if len(hunks) == 0 || len(line) == 0 || line[0] == '\\' {
+			continue
+		}
+
+		hunk := &hunks[len(hunks)-1]
+		switch line[0] {
+		case '+':
+			hunk.Lines = append(hunk.Lines, cmn.GitDiffLine{Type: "add", Text: line[1:], NewNumber: newNumber})
+			newNumber++
+		case '-':
+			hunk.Lines = append(hunk.Lines, cmn.GitDiffLine{Type: "delete", Text: line[1:], OldNumber: oldNumber})
+			oldNumber++
+		default:
+			hunk.Lines = append(hunk.Lines, cmn.GitDiffLine{Type: "context", Text: line[1:], OldNumber: oldNumber, NewNumber: newNumber})
+			oldNumber++
+			newNumber++
+		}
+	}
+
+	return hunks
+}
+
+// newGitFileDiff builds the file diff for a change of a commit.
+func newGitFileDiff(change *object.Change) (cmn.GitFileDiff, error) {
+	fileDiff := cmn.GitFileDiff{
+		OldPath: change.From.Name,
+		NewPath: change.To.Name,
+	}
+
+	action, err := change.Action()
+	if err != nil {
+		return fileDiff, err
+	}
+	switch {
+	case action == merkletrie.Insert:
+		fileDiff.Type = "add"
+	case action == merkletrie.Delete:
+		fileDiff.Type = "delete"
+	case fileDiff.OldPath != fileDiff.NewPath:
+		fileDiff.Type = "rename"
+	default:
+		fileDiff.Type = "modify"
+	}
+
+	patch, err := change.Patch()
+	if err != nil {
+		return fileDiff, err
+	}
+	for _, filePatch := range patch.FilePatches() {
+		fileDiff.Binary = fileDiff.Binary || filePatch.IsBinary()
+	}
+	fileDiff.Patch = patch.String()
+	fileDiff.Hunks = parseHunks(fileDiff.Patch)
+
+	return fileDiff, nil
+}
+
+// commitDiff builds the file diffs of the commit against its first parent, detecting renames.
+func commitDiff(commit *object.Commit) ([]cmn.GitFileDiff, error) {
+	funcName := "processors.commitDiff"
+	cmn.Debug("%s: begin", funcName)
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	// The root commit is compared to an empty tree.
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := object.DiffTreeWithOptions(context.Background(), parentTree, tree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	fileDiffs := make([]cmn.GitFileDiff, 0, len(changes))
+	for i := range changes {
+		fileDiff, err := newGitFileDiff(changes[i])
+		if err != nil {
+			return nil, err
+		}
+		fileDiffs = append(fileDiffs, fileDiff)
+	}
+	cmn.Debug("%s: commit %s: %d file diffs", funcName, commit.Hash.String()[0:7], len(fileDiffs))
+
+	cmn.Debug("%s: end", funcName)
+	return fileDiffs, nil
+}
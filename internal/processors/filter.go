@@ -0,0 +1,285 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// firstParentIter iterates the commits following only the first parent of each commit,
+// stopping at an excluded commit or a shallow boundary.
+type firstParentIter struct {
+	next    *object.Commit
+	exclude map[plumbing.Hash]bool
+	shallow *shallowHistory
+}
+
+func (i *firstParentIter) Next() (*object.Commit, error) {
+	if i.next == nil || i.exclude[i.next.Hash] {
+		return nil, io.EOF
+	}
+
+	commit := i.next
+	i.next = nil
+	if commit.NumParents() > 0 && !i.shallow.isBoundary(commit.Hash) {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		i.next = parent
+	}
+
+	return commit, nil
+}
+
+func (i *firstParentIter) ForEach(cb func(*object.Commit) error) error {
+	for {
+		commit, err := i.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = cb(commit)
+		if err == storer.ErrStop {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func (i *firstParentIter) Close() {
+	i.next = nil
+}
+
+// matchSegments reports whether the path segments match the pattern segments; a `**`
+// segment matches any number of path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], name[0]); err != nil || !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// matchGlob reports whether the slash separated file name matches the glob pattern, the
+// pattern matching either the file itself or a directory containing it.
+func matchGlob(pattern, name string) bool {
+	if pattern == name {
+		return true
+	}
+
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	nameSegments := strings.Split(name, "/")
+	for i := 1; i <= len(nameSegments); i++ {
+		if matchSegments(patternSegments, nameSegments[:i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchAny reports whether the value matches any of the patterns.
+func matchAny(patterns []*regexp.Regexp, value string) bool {
+	for i := range patterns {
+		if patterns[i].MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compilePatterns compiles the list of regular expressions.
+func compilePatterns(values []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(values))
+	for i := range values {
+		pattern, err := regexp.Compile(values[i])
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
+
+// touchesPaths reports whether the commit changes any file matching the globs; a merge
+// commit only when it differs from each of its parents.
+func touchesPaths(commit *object.Commit, globs []string, boundary bool) (bool, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return false, err
+	}
+
+	match := func(changes object.Changes) bool {
+		for i := range changes {
+			for _, name := range []string{changes[i].From.Name, changes[i].To.Name} {
+				for j := range globs {
+					if name != "" && matchGlob(globs[j], name) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	// The root commit, or a shallow boundary commit, adds every file of its tree.
+	if commit.NumParents() == 0 || boundary {
+		changes, err := object.DiffTree(nil, tree)
+		if err != nil {
+			return false, err
+		}
+		return match(changes), nil
+	}
+
+	parents := commit.Parents()
+	defer parents.Close()
+	touches := true
+	err = parents.ForEach(func(parent *object.Commit) error {
+		parentTree, err := parent.Tree()
+		if err != nil {
+			return err
+		}
+		changes, err := object.DiffTree(parentTree, tree)
+		if err != nil {
+			return err
+		}
+		if !match(changes) {
+			touches = false
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return touches, nil
+}
+
+// commitMatcher builds the filter of the merge, paths and author options of the processor.
+func commitMatcher(src *gitSource, processor cmn.GitProcessor) (func(*object.Commit) (bool, error), error) {
+	authors, err := compilePatterns(processor.Authors)
+	if err != nil {
+		return nil, err
+	}
+	excludeAuthors, err := compilePatterns(processor.ExcludeAuthors)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(commit *object.Commit) (bool, error) {
+		merge := commit.NumParents() > 1
+		if (processor.NoMerges && merge) || (processor.OnlyMerges && !merge) {
+			return false, nil
+		}
+
+		if len(processor.Paths) > 0 {
+			touches, err := touchesPaths(commit, processor.Paths, src.shallow.isBoundary(commit.Hash))
+			if err != nil || !touches {
+				return false, err
+			}
+		}
+
+		author := fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email)
+		if (len(authors) > 0 && !matchAny(authors, author)) || matchAny(excludeAuthors, author) {
+			return false, nil
+		}
+
+		return true, nil
+	}, nil
+}
+
+// gitLog iterates the commits reachable from the hash, newest first, calling fn for each
+// commit passing the commit filters of the processor. Commits in the exclude set, and their
+// history, are not visited.
+func gitLog(src *gitSource, hash plumbing.Hash, exclude map[plumbing.Hash]bool, processor cmn.GitProcessor, fn func(*object.Commit) error) error {
+	funcName := "processors.gitLog"
+	cmn.Debug("%s: begin", funcName)
+
+	since, err := cmn.ParseTime(processor.Since)
+	if err != nil {
+		return err
+	}
+	until, err := cmn.ParseTime(processor.Until)
+	if err != nil {
+		return err
+	}
+	matches, err := commitMatcher(src, processor)
+	if err != nil {
+		return err
+	}
+
+	// Get the commit history in an iterator.
+	commit, err := src.repo.CommitObject(hash)
+	if err != nil {
+		return err
+	}
+	var commitIter object.CommitIter
+	if processor.FirstParent {
+		cmn.Debug("%s: following first parent", funcName)
+		commitIter = &firstParentIter{next: commit, exclude: exclude, shallow: src.shallow}
+	} else {
+		commitIter = object.NewCommitPreorderIter(commit, exclude, src.shallow.ignore())
+	}
+	if since != nil || until != nil {
+		cmn.Debug("%s: filtering since: %v; until: %v", funcName, since, until)
+		commitIter = object.NewCommitLimitIterFromIter(commitIter, object.LogLimitOptions{Since: since, Until: until})
+	}
+	defer commitIter.Close()
+	cmn.Debug("%s: created git history iterator", funcName)
+
+	// Iterate through the commits.
+	count := 0
+	err = commitIter.ForEach(func(commit *object.Commit) error {
+		match, err := matches(commit)
+		if err != nil {
+			return err
+		}
+		if !match {
+			return nil
+		}
+
+		if processor.MaxCount > 0 && count >= processor.MaxCount {
+			cmn.Debug("%s: reached max count: %d", funcName, processor.MaxCount)
+			return storer.ErrStop
+		}
+		count++
+
+		return fn(commit)
+	})
+	if err != nil {
+		return err
+	}
+	cmn.Debug("%s: %d commits", funcName, count)
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
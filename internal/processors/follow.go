@@ -0,0 +1,193 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"context"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// followChange is a change of a commit against one of its parents; the paths before and
+// after, either empty for an added or deleted file.
+type followChange struct {
+	from string
+	to   string
+}
+
+// followHistory is the history of the ref of a source with the changes of each commit
+// against each of its parents, with rename detection. It is built once per processor and
+// shared by the files it follows.
+type followHistory struct {
+	// commits are ordered topologically, so that renames are seen before the history under
+	// the old path.
+	commits []*object.Commit
+	// changes are the changes of each commit, by parent.
+	changes map[plumbing.Hash][][]followChange
+	// matches reports whether a commit passes the commit filters of the processor.
+	matches func(*object.Commit) (bool, error)
+}
+
+// followChanges compares the commit to each of its parents with rename detection.
+func followChanges(commit *object.Commit, boundary bool) ([][]followChange, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	// The root commit, or a shallow boundary commit, is compared to an empty tree.
+	parentTrees := []*object.Tree{nil}
+	if commit.NumParents() > 0 && !boundary {
+		parentTrees = parentTrees[:0]
+		parents := commit.Parents()
+		defer parents.Close()
+		err = parents.ForEach(func(parent *object.Commit) error {
+			parentTree, err := parent.Tree()
+			if err != nil {
+				return err
+			}
+			parentTrees = append(parentTrees, parentTree)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make([][]followChange, 0, len(parentTrees))
+	for i := range parentTrees {
+		diff, err := object.DiffTreeWithOptions(context.Background(), parentTrees[i], tree, object.DefaultDiffTreeOptions)
+		if err != nil {
+			return nil, err
+		}
+		parentChanges := make([]followChange, 0, len(diff))
+		for j := range diff {
+			parentChanges = append(parentChanges, followChange{from: diff[j].From.Name, to: diff[j].To.Name})
+		}
+		changes = append(changes, parentChanges)
+	}
+
+	return changes, nil
+}
+
+// newFollowHistory collects the history of the ref of the source within the range of the
+// processor, with the changes of every commit; renames are followed through every commit,
+// so the path and author filters are left to followLog.
+func newFollowHistory(src *gitSource, processor cmn.GitProcessor) (*followHistory, error) {
+	funcName := "processors.newFollowHistory"
+	cmn.Debug("%s: begin", funcName)
+
+	history := processor
+	history.Paths = nil
+	history.Authors = nil
+	history.ExcludeAuthors = nil
+	history.NoMerges = false
+	history.OnlyMerges = false
+	history.MaxCount = 0
+	commits := map[plumbing.Hash]*object.Commit{}
+	err := gitLog(src, src.ref.Hash(), nil, history, func(commit *object.Commit) error {
+		commits[commit.Hash] = commit
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	processor.Paths = nil
+	matches, err := commitMatcher(src, processor)
+	if err != nil {
+		return nil, err
+	}
+
+	follow := &followHistory{
+		commits: topoSort(commits),
+		changes: make(map[plumbing.Hash][][]followChange, len(commits)),
+		matches: matches,
+	}
+	for _, commit := range follow.commits {
+		follow.changes[commit.Hash], err = followChanges(commit, src.shallow.isBoundary(commit.Hash))
+		if err != nil {
+			return nil, err
+		}
+	}
+	cmn.Debug("%s: %d commits", funcName, len(follow.commits))
+
+	cmn.Debug("%s: end", funcName)
+	return follow, nil
+}
+
+// touches reports whether the commit changes any of the paths against every parent, as with
+// touchesPaths, and the old paths of files renamed into the paths against any parent.
+func (h *followHistory) touches(commit *object.Commit, paths map[string]bool) (bool, []string) {
+	touches := true
+	var renamed []string
+	for _, changes := range h.changes[commit.Hash] {
+		match := false
+		for _, change := range changes {
+			if paths[change.from] || paths[change.to] {
+				match = true
+			}
+			if paths[change.to] && change.from != "" && change.from != change.to {
+				renamed = append(renamed, change.from)
+			}
+		}
+		if !match {
+			touches = false
+		}
+	}
+
+	return touches, renamed
+}
+
+// followLog iterates the commits of the history changing the file at the path, newest first,
+// following the file across renames; fn is called for each commit passing the commit filters
+// of the processor. Returns the old paths of the file, most recent first.
+func followLog(history *followHistory, processor cmn.GitProcessor, path string, fn func(*object.Commit) error) ([]string, error) {
+	funcName := "processors.followLog"
+	cmn.Debug("%s: begin", funcName)
+
+	paths := map[string]bool{path: true}
+	var oldPaths []string
+	sorted := history.commits
+	count := 0
+	for i := len(sorted) - 1; i >= 0; i-- {
+		touches, renamed := history.touches(sorted[i], paths)
+		for _, name := range renamed {
+			if !paths[name] {
+				cmn.Debug("%s: %s: renamed from %s", funcName, sorted[i].Hash.String()[:7], name)
+				paths[name] = true
+				oldPaths = append(oldPaths, name)
+			}
+		}
+		if !touches {
+			continue
+		}
+
+		match, err := history.matches(sorted[i])
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+		if processor.MaxCount > 0 && count >= processor.MaxCount {
+			cmn.Debug("%s: reached max count: %d", funcName, processor.MaxCount)
+			break
+		}
+		count++
+
+		err = fn(sorted[i])
+		if err == storer.ErrStop {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	cmn.Debug("%s: %d commits; %d old paths", funcName, count, len(oldPaths))
+
+	cmn.Debug("%s: end", funcName)
+	return oldPaths, nil
+}
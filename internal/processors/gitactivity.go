@@ -0,0 +1,171 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// periodStart returns the key and start of the period containing the date; in the time
+// zone of the date, as shown by git.
+func periodStart(when time.Time, period string) (string, time.Time) {
+	year, month, day := when.Date()
+	switch period {
+	case "day":
+		start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return start.Format(time.DateOnly), start
+	case "week":
+		isoYear, week := when.ISOWeek()
+		start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		return fmt.Sprintf("%04d-W%02d", isoYear, week), start
+	case "year":
+		return fmt.Sprintf("%04d", year), time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return fmt.Sprintf("%04d-%02d", year, month), time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// topDirectory returns the top-level directory of the path; `.` for files at the root.
+func topDirectory(path string) string {
+	if dir, _, ok := strings.Cut(path, "/"); ok {
+		return dir
+	}
+
+	return "."
+}
+
+// newGitActivity aggregates the git log of the source by period, and by author or
+// top-level directory if configured. Merge commits are counted, but not their changed lines.
+func newGitActivity(src *gitSource, processor cmn.GitProcessor) (cmn.GitActivity, error) {
+	funcName := "processors.newGitActivity"
+	cmn.Debug("%s: begin", funcName)
+
+	activity := cmn.GitActivity{
+		Period:  strings.ToLower(processor.Period),
+		GroupBy: strings.ToLower(processor.GroupBy),
+		Ref:     src.info,
+	}
+	if activity.Period == "" {
+		activity.Period = "month"
+	}
+	cmn.Debug("%s: period: %s; group by: %s", funcName, activity.Period, activity.GroupBy)
+
+	buckets := map[string]*cmn.GitActivityBucket{}
+	authors := map[string]map[string]bool{}
+	groups := map[string]bool{}
+
+	// Iterate through the commits; newest first.
+	err := sourceLog(src, nil, processor, func(member *gitSource, commit *object.Commit) error {
+		stats, err := commitStats(member, commit)
+		if err != nil {
+			return err
+		}
+		activity.Commits++
+		author := member.mailmap.resolve(commit.Author.Name, commit.Author.Email)
+		key, start := periodStart(commit.Author.When, activity.Period)
+
+		// The lines changed by group; a commit counts once in each of its groups.
+		lines := map[string][2]int{}
+		switch activity.GroupBy {
+		case "author":
+			lines[author.Name] = [2]int{}
+		case "directory":
+			for i := range stats {
+				lines[topDirectory(statsPath(stats[i].Name))] = [2]int{}
+			}
+		default:
+			lines[""] = [2]int{}
+		}
+		if commit.NumParents() <= 1 {
+			for i := range stats {
+				group := ""
+				switch activity.GroupBy {
+				case "author":
+					group = author.Name
+				case "directory":
+					group = topDirectory(statsPath(stats[i].Name))
+				}
+				counts := lines[group]
+				counts[0] += stats[i].Addition
+				counts[1] += stats[i].Deletion
+				lines[group] = counts
+			}
+		}
+
+		for group, counts := range lines {
+			id := key + "\x00" + group
+			bucket, ok := buckets[id]
+			if !ok {
+				bucket = &cmn.GitActivityBucket{Key: key, Start: start, Group: group}
+				buckets[id] = bucket
+				authors[id] = map[string]bool{}
+			}
+			bucket.Commits++
+			bucket.Additions += counts[0]
+			bucket.Deletions += counts[1]
+			email := strings.ToLower(author.Email)
+			if !authors[id][email] {
+				authors[id][email] = true
+				bucket.Authors = append(bucket.Authors, author)
+			}
+			if group != "" {
+				groups[group] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return activity, err
+	}
+
+	for _, bucket := range buckets {
+		activity.Buckets = append(activity.Buckets, *bucket)
+	}
+	sort.Slice(activity.Buckets, func(i, j int) bool {
+		if !activity.Buckets[i].Start.Equal(activity.Buckets[j].Start) {
+			return activity.Buckets[i].Start.Before(activity.Buckets[j].Start)
+		}
+		return activity.Buckets[i].Group < activity.Buckets[j].Group
+	})
+	for group := range groups {
+		activity.Groups = append(activity.Groups, group)
+	}
+	sort.Strings(activity.Groups)
+	cmn.Debug("%s: %d buckets of %d commits", funcName, len(activity.Buckets), activity.Commits)
+
+	cmn.Debug("%s: end", funcName)
+	return activity, nil
+}
+
+// gitActivity - Process Activity mode git log processor.
+func gitActivity(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitActivity"
+	cmn.Debug("%s: begin", funcName)
+
+	activity, err := newGitActivity(src, processor)
+	if err != nil {
+		return err
+	}
+
+	scr, err := gitScript(src, processor, "activity")
+	if err != nil {
+		return err
+	}
+
+	err = gitOutput(src, processor, scr, activity, map[string]tengo.Object{
+		"activity": gitActivityObject(activity),
+	})
+	if err != nil {
+		return err
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
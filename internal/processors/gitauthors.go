@@ -0,0 +1,117 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// statsPath returns the path of a file stat; renames are named `old => new`.
+func statsPath(name string) string {
+	if _, renamed, ok := strings.Cut(name, " => "); ok {
+		return renamed
+	}
+
+	return name
+}
+
+// newGitContributors aggregates the git log of the source into per-author statistics of
+// the canonical authors, ordered by commit count. Merge commits are counted, but not their changed lines or files.
+func newGitContributors(src *gitSource, processor cmn.GitProcessor) (cmn.GitContributors, error) {
+	funcName := "processors.newGitContributors"
+	cmn.Debug("%s: begin", funcName)
+
+	contributors := cmn.GitContributors{Ref: src.info}
+	authors := map[string]*cmn.GitContributor{}
+	files := map[string]map[string]bool{}
+
+	// Iterate through the commits; not ordered by author date.
+	err := sourceLog(src, nil, processor, func(member *gitSource, commit *object.Commit) error {
+		entry, err := newGitLogEntry(member, processor, commit)
+		if err != nil {
+			return err
+		}
+		contributors.Commits++
+
+		key := strings.ToLower(entry.Author.Email)
+		author, ok := authors[key]
+		if !ok {
+			author = &cmn.GitContributor{
+				Name:      entry.Author.Name,
+				Email:     entry.Author.Email,
+				FirstDate: entry.Commit.Author.When,
+				LastDate:  entry.Commit.Author.When,
+			}
+			authors[key] = author
+			files[key] = map[string]bool{}
+		}
+		author.Commits++
+		if entry.Commit.Author.When.Before(author.FirstDate) {
+			author.FirstDate = entry.Commit.Author.When
+		}
+		if entry.Commit.Author.When.After(author.LastDate) {
+			author.LastDate = entry.Commit.Author.When
+		}
+
+		if commit.NumParents() > 1 {
+			return nil
+		}
+		for i := range entry.Stats {
+			author.Additions += entry.Stats[i].Addition
+			author.Deletions += entry.Stats[i].Deletion
+			files[key][statsPath(entry.Stats[i].Name)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return contributors, err
+	}
+
+	for key, author := range authors {
+		for file := range files[key] {
+			author.Files = append(author.Files, file)
+		}
+		sort.Strings(author.Files)
+		contributors.Authors = append(contributors.Authors, *author)
+	}
+	sort.Slice(contributors.Authors, func(i, j int) bool {
+		if contributors.Authors[i].Commits != contributors.Authors[j].Commits {
+			return contributors.Authors[i].Commits > contributors.Authors[j].Commits
+		}
+		return contributors.Authors[i].Name < contributors.Authors[j].Name
+	})
+	cmn.Debug("%s: %d authors of %d commits", funcName, len(contributors.Authors), contributors.Commits)
+
+	cmn.Debug("%s: end", funcName)
+	return contributors, nil
+}
+
+// gitAuthors - Process Authors mode git log processor.
+func gitAuthors(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitAuthors"
+	cmn.Debug("%s: begin", funcName)
+
+	contributors, err := newGitContributors(src, processor)
+	if err != nil {
+		return err
+	}
+
+	scr, err := gitScript(src, processor, "authors")
+	if err != nil {
+		return err
+	}
+
+	err = gitOutput(src, processor, scr, contributors, map[string]tengo.Object{
+		"authors": gitContributorsObject(contributors),
+	})
+	if err != nil {
+		return err
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
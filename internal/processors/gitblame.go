@@ -0,0 +1,141 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// errLFSFile is returned when blaming a Git LFS file; its lines are those of the pointer.
+var errLFSFile = errors.New("git lfs file")
+
+// newGitBlame builds the line-level authorship of the file at the given path, as of the commit.
+func newGitBlame(src *gitSource, commit *object.Commit, path string) (cmn.GitBlame, error) {
+	funcName := "processors.newGitBlame"
+	cmn.Debug("%s: begin", funcName)
+
+	gitBlame := cmn.GitBlame{Path: path, Ref: src.info}
+
+	relPath, err := repoRelPath(src.repo, path)
+	if err != nil {
+		return gitBlame, err
+	}
+	cmn.Debug("%s: repo path: %s", funcName, relPath)
+
+	file, err := commit.File(relPath)
+	if err != nil {
+		return gitBlame, err
+	}
+	pointer, err := fileLFSPointer(file)
+	if err != nil {
+		return gitBlame, err
+	}
+	if pointer != nil {
+		return gitBlame, errLFSFile
+	}
+
+	result, err := git.Blame(commit, relPath)
+	if err != nil {
+		return gitBlame, err
+	}
+
+	// Count the lines of each author, noting the most recent change.
+	authors := map[string]*cmn.GitBlameAuthor{}
+	for i, line := range result.Lines {
+		identity := src.mailmap.resolve(line.AuthorName, line.Author)
+		key := strings.ToLower(identity.Email)
+		author, ok := authors[key]
+		if !ok {
+			author = &cmn.GitBlameAuthor{Name: identity.Name, Email: identity.Email}
+			authors[key] = author
+		}
+		author.Lines++
+
+		if i == 0 || line.Date.After(gitBlame.LastChange.Date) {
+			gitBlame.LastChange = cmn.GitBlameLine{
+				Number: i + 1,
+				Text:   line.Text,
+				Name:   identity.Name,
+				Email:  identity.Email,
+				Date:   line.Date,
+				Hash:   line.Hash.String(),
+			}
+		}
+	}
+	gitBlame.Lines = len(result.Lines)
+
+	// Order the authors by their share of the file.
+	for _, author := range authors {
+		author.Share = float64(author.Lines) / float64(gitBlame.Lines)
+		gitBlame.Authors = append(gitBlame.Authors, *author)
+	}
+	sort.Slice(gitBlame.Authors, func(i, j int) bool {
+		if gitBlame.Authors[i].Lines != gitBlame.Authors[j].Lines {
+			return gitBlame.Authors[i].Lines > gitBlame.Authors[j].Lines
+		}
+		return gitBlame.Authors[i].Name < gitBlame.Authors[j].Name
+	})
+	cmn.Debug("%s: %s: %d lines; %d authors", funcName, path, gitBlame.Lines, len(gitBlame.Authors))
+
+	cmn.Debug("%s: end", funcName)
+	return gitBlame, nil
+}
+
+// gitBlames - Process Blame mode git log processor.
+func gitBlames(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitBlames"
+	cmn.Debug("%s: begin", funcName)
+
+	commit, err := commitObject(src, src.ref.Hash())
+	if err != nil {
+		return err
+	}
+	cmn.Debug("%s: head commit: %v", funcName, commit.Hash.String())
+
+	// Walk the tree configured in the processor...retrieving the matched files.
+	files, err := processorFiles(processor)
+	if err != nil {
+		return err
+	}
+	files, err = trackedFiles(src, processor, files)
+	if err != nil {
+		return err
+	}
+
+	scr, err := gitScript(src, processor, "blame")
+	if err != nil {
+		return err
+	}
+
+	// Process the blame of each file.
+	for i := range files {
+		gitBlame, err := newGitBlame(src, commit, files[i])
+		if err == object.ErrFileNotFound {
+			cmn.Debug("%s: %s: not committed, skipping", funcName, files[i])
+			continue
+		}
+		if err == errLFSFile {
+			cmn.Debug("%s: %s: git lfs file, skipping", funcName, files[i])
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = gitOutput(src, processor, scr, gitBlame, map[string]tengo.Object{
+			"blame": gitBlameObject(gitBlame),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
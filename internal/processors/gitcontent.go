@@ -0,0 +1,224 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// revisionCommit resolves the commit of a template or script value; a commit, a log entry,
+// or a revision such as a hash, branch or tag. Revisions of a merged source resolve in the
+// first of its repositories having the revision.
+func revisionCommit(src *gitSource, rev any) (*object.Commit, error) {
+	switch value := rev.(type) {
+	case *object.Commit:
+		return value, nil
+	case cmn.GitLogEntry:
+		return value.Commit, nil
+	case *cmn.GitLogEntry:
+		return value.Commit, nil
+	case string:
+		var err error
+		for _, member := range src.sources() {
+			var hash *plumbing.Hash
+			hash, err = member.repo.ResolveRevision(plumbing.Revision(value))
+			if err == nil {
+				return member.repo.CommitObject(*hash)
+			}
+		}
+		return nil, fmt.Errorf("invalid revision: %s: %w", value, err)
+	}
+
+	return nil, fmt.Errorf("invalid revision: %v", rev)
+}
+
+// treePath cleans the path into a path relative to the root of the tree.
+func treePath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// readGitFile reads the content of the file at the revision.
+func readGitFile(src *gitSource, rev any, name string) (string, error) {
+	commit, err := revisionCommit(src, rev)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := commit.File(treePath(name))
+	if err != nil {
+		return "", fmt.Errorf("%s: %s: %w", commit.Hash.String()[:7], name, err)
+	}
+
+	content, _, err := readGitBlob(src, file)
+	return content, err
+}
+
+// readGitLFS reads the Git LFS pointer of the file at the revision; nil if the file is
+// not a Git LFS pointer.
+func readGitLFS(src *gitSource, rev any, name string) (*cmn.GitLFSPointer, error) {
+	commit, err := revisionCommit(src, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := commit.File(treePath(name))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", commit.Hash.String()[:7], name, err)
+	}
+
+	return fileLFSPointer(file)
+}
+
+// listGitTree lists the files beneath the directory at the revision, sorted by path; the
+// size of a Git LFS pointer is that of its object.
+func listGitTree(src *gitSource, rev any, dir string) ([]cmn.GitTreeEntry, error) {
+	commit, err := revisionCommit(src, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	dir = treePath(dir)
+	if dir != "" {
+		tree, err = tree.Tree(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", commit.Hash.String()[:7], dir, err)
+		}
+	}
+
+	var entries []cmn.GitTreeEntry
+	err = tree.Files().ForEach(func(file *object.File) error {
+		entry := cmn.GitTreeEntry{
+			Path: path.Join(dir, file.Name),
+			Name: path.Base(file.Name),
+			Size: file.Size,
+			Hash: file.Hash.String(),
+		}
+		entry.LFS, err = fileLFSPointer(file)
+		if err != nil {
+			return err
+		}
+		if entry.LFS != nil {
+			entry.Size = entry.LFS.Size
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return entries, nil
+}
+
+// gitFuncMap provides the template functions reading files from the history of the repository.
+func gitFuncMap(src *gitSource) template.FuncMap {
+	return template.FuncMap{
+		"gitFile": func(rev any, name string) (string, error) {
+			return readGitFile(src, rev, name)
+		},
+		"gitTree": func(rev any, dir string) ([]cmn.GitTreeEntry, error) {
+			return listGitTree(src, rev, dir)
+		},
+		"gitLFS": func(rev any, name string) (*cmn.GitLFSPointer, error) {
+			return readGitLFS(src, rev, name)
+		},
+		"linkReferences": func(text any) (string, error) {
+			switch t := text.(type) {
+			case cmn.GitLogEntry:
+				return linkReferences(src.references, t.Commit.Message)
+			case *cmn.GitLogEntry:
+				return linkReferences(src.references, t.Commit.Message)
+			case *object.Commit:
+				return linkReferences(src.references, t.Message)
+			case string:
+				return linkReferences(src.references, t)
+			}
+			return "", fmt.Errorf("invalid text for linkReferences: %T", text)
+		},
+	}
+}
+
+// gitModule provides the `git` Tengo module reading files from the history of the
+// repository; with `file(rev, path)`, `tree(rev, dir)` and `lfs(rev, path)`, and linking
+// the issue and ticket references of a text with `link_references(text)`.
+func gitModule(src *gitSource) map[string]tengo.Object {
+	args := func(args []tengo.Object) (string, string, error) {
+		if len(args) != 2 {
+			return "", "", tengo.ErrWrongNumArguments
+		}
+		rev, ok := tengo.ToString(args[0])
+		if !ok {
+			return "", "", tengo.ErrInvalidArgumentType{Name: "first", Expected: "string", Found: args[0].TypeName()}
+		}
+		name, ok := tengo.ToString(args[1])
+		if !ok {
+			return "", "", tengo.ErrInvalidArgumentType{Name: "second", Expected: "string", Found: args[1].TypeName()}
+		}
+		return rev, name, nil
+	}
+
+	return map[string]tengo.Object{
+		"file": &tengo.UserFunction{Name: "file", Value: func(a ...tengo.Object) (tengo.Object, error) {
+			rev, name, err := args(a)
+			if err != nil {
+				return nil, err
+			}
+			content, err := readGitFile(src, rev, name)
+			if err != nil {
+				return &tengo.Error{Value: &tengo.String{Value: err.Error()}}, nil
+			}
+			return &tengo.String{Value: content}, nil
+		}},
+		"tree": &tengo.UserFunction{Name: "tree", Value: func(a ...tengo.Object) (tengo.Object, error) {
+			rev, dir, err := args(a)
+			if err != nil {
+				return nil, err
+			}
+			entries, err := listGitTree(src, rev, dir)
+			if err != nil {
+				return &tengo.Error{Value: &tengo.String{Value: err.Error()}}, nil
+			}
+			return gitTreeObject(entries), nil
+		}},
+		"lfs": &tengo.UserFunction{Name: "lfs", Value: func(a ...tengo.Object) (tengo.Object, error) {
+			rev, name, err := args(a)
+			if err != nil {
+				return nil, err
+			}
+			pointer, err := readGitLFS(src, rev, name)
+			if err != nil {
+				return &tengo.Error{Value: &tengo.String{Value: err.Error()}}, nil
+			}
+			return gitLFSObject(pointer), nil
+		}},
+		"link_references": &tengo.UserFunction{Name: "link_references", Value: func(a ...tengo.Object) (tengo.Object, error) {
+			if len(a) != 1 {
+				return nil, tengo.ErrWrongNumArguments
+			}
+			text, ok := tengo.ToString(a[0])
+			if !ok {
+				return nil, tengo.ErrInvalidArgumentType{Name: "first", Expected: "string", Found: a[0].TypeName()}
+			}
+			linked, err := linkReferences(src.references, text)
+			if err != nil {
+				return &tengo.Error{Value: &tengo.String{Value: err.Error()}}, nil
+			}
+			return &tengo.String{Value: linked}, nil
+		}},
+	}
+}
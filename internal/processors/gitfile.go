@@ -0,0 +1,230 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// repoRelPath converts the file path into a slash separated path relative to the
+// root of the repository worktree, as used by the git log.
+func repoRelPath(repo *git.Repository, path string) (string, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	root, err := filepath.Abs(worktree.Filesystem.Root())
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// newGitFile builds the git log of the file at the given path; the commit filters of the
+// processor apply, with its paths replaced by the file. With `follow`, the log is looked up
+// in the history shared by the files of the processor.
+func newGitFile(src *gitSource, processor cmn.GitProcessor, history *followHistory, path string) (cmn.GitFile, error) {
+	funcName := "processors.newGitFile"
+	cmn.Debug("%s: begin", funcName)
+
+	gitFile := cmn.GitFile{Path: path, Ref: src.info}
+
+	relPath, err := repoRelPath(src.repo, path)
+	if err != nil {
+		return gitFile, err
+	}
+	cmn.Debug("%s: repo path: %s", funcName, relPath)
+
+	// Uncommitted changes of the file come first, as a synthetic working copy entry.
+	seen := map[string]bool{}
+	if processor.WorkingCopy && src.worktree != nil && src.worktree.changed[relPath] {
+		entry := workingCopyEntry(src)
+		gitFile.Commits = append(gitFile.Commits, entry)
+		seen[strings.ToLower(entry.Author.Email)] = true
+		gitFile.Authors = append(gitFile.Authors, entry.Author)
+		cmn.Debug("%s: %s: uncommitted changes", funcName, relPath)
+	}
+
+	// Iterate through the commits of the file, noting the distinct authors.
+	add := func(commit *object.Commit) error {
+		entry, err := newGitLogEntry(src, processor, commit)
+		if err != nil {
+			return err
+		}
+		gitFile.Commits = append(gitFile.Commits, entry)
+
+		key := strings.ToLower(entry.Author.Email)
+		if !seen[key] {
+			seen[key] = true
+			gitFile.Authors = append(gitFile.Authors, entry.Author)
+		}
+		return nil
+	}
+	if processor.Follow {
+		gitFile.OldPaths, err = followLog(history, processor, relPath, add)
+	} else {
+		processor.Paths = []string{relPath}
+		err = gitLog(src, src.ref.Hash(), nil, processor, add)
+	}
+	if err != nil {
+		return gitFile, err
+	}
+
+	// The log is not ordered by date; side branches follow the first parent chain.
+	gitFile.Count = len(gitFile.Commits)
+	for i := range gitFile.Commits {
+		if gitFile.Commits[i].WorkingCopy {
+			continue
+		}
+		when := gitFile.Commits[i].Commit.Author.When
+		if gitFile.FirstDate.IsZero() || when.Before(gitFile.FirstDate) {
+			gitFile.FirstDate = when
+		}
+		if gitFile.LastDate.IsZero() || when.After(gitFile.LastDate) {
+			gitFile.LastDate = when
+		}
+	}
+	cmn.Debug("%s: %s: %d commits", funcName, path, gitFile.Count)
+
+	cmn.Debug("%s: end", funcName)
+	return gitFile, nil
+}
+
+// processorFiles walks the path configured in the git processor, retrieving the files
+// matching its pattern.
+func processorFiles(processor cmn.GitProcessor) ([]string, error) {
+	funcName := "processors.processorFiles"
+	cmn.Debug("%s: begin", funcName)
+
+	// Define the path to walk.
+	path := processor.Path
+	if path == "" {
+		path = "."
+	}
+	cmn.Debug("%s: path: %s", funcName, path)
+	cmn.Debug("%s: pattern: %s", funcName, processor.Pattern)
+
+	files, err := cmn.WalkMatch(path, processor.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	cmn.Debug("%s: found %d files", funcName, len(files))
+
+	cmn.Debug("%s: end", funcName)
+	return files, nil
+}
+
+// trackedFiles filters the files matched by the processor to those tracked in the commit of
+// the source; with `working_copy`, also the uncommitted files of the working tree. Files
+// outside the worktree of the repository of the source, such as those of the other
+// repositories of a merged source, are skipped.
+func trackedFiles(src *gitSource, processor cmn.GitProcessor, matches []string) ([]string, error) {
+	funcName := "processors.trackedFiles"
+	cmn.Debug("%s: begin", funcName)
+
+	commit, err := commitObject(src, src.ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for i := range matches {
+		ok, err := inWorktree(src, matches[i])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			cmn.Debug("%s: %s: not in repo %s, skipping", funcName, matches[i], src.info.Repo)
+			continue
+		}
+		relPath, err := repoRelPath(src.repo, matches[i])
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tree.File(relPath)
+		if err == object.ErrFileNotFound {
+			if processor.WorkingCopy && src.worktree != nil && src.worktree.changed[relPath] {
+				files = append(files, matches[i])
+				continue
+			}
+			cmn.Debug("%s: %s: not tracked, skipping", funcName, matches[i])
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches[i])
+	}
+	cmn.Debug("%s: %d tracked files", funcName, len(files))
+
+	cmn.Debug("%s: end", funcName)
+	return files, nil
+}
+
+// gitFiles - Process File mode git log processor.
+func gitFiles(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitFiles"
+	cmn.Debug("%s: begin", funcName)
+
+	// Walk the tree configured in the processor...retrieving the matched files.
+	files, err := processorFiles(processor)
+	if err != nil {
+		return err
+	}
+	files, err = trackedFiles(src, processor, files)
+	if err != nil {
+		return err
+	}
+
+	scr, err := gitScript(src, processor, "history")
+	if err != nil {
+		return err
+	}
+
+	// Collect the changes of the history once; each file follows its renames through them.
+	var history *followHistory
+	if processor.Follow && len(files) > 0 {
+		history, err = newFollowHistory(src, processor)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Process the history of each file.
+	for i := range files {
+		gitFile, err := newGitFile(src, processor, history, files[i])
+		if err != nil {
+			return err
+		}
+
+		err = gitOutput(src, processor, scr, gitFile, map[string]tengo.Object{
+			"history": gitFileObject(gitFile),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
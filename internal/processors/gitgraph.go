@@ -0,0 +1,338 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"container/heap"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// mergeBranch matches the merged branch of a default merge commit message.
+var mergeBranch = regexp.MustCompile(`^Merge (?:remote-tracking )?branch '([^']+)'`)
+
+// decorations retrieves the names of the local branches and tags of each commit.
+func decorations(repo *git.Repository) (map[plumbing.Hash][]string, map[plumbing.Hash][]string, error) {
+	branches := map[plumbing.Hash][]string{}
+	tags := map[plumbing.Hash][]string{}
+
+	iter, err := repo.Branches()
+	if err != nil {
+		return nil, nil, err
+	}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		branches[ref.Hash()] = append(branches[ref.Hash()], ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iter, err = repo.Tags()
+	if err != nil {
+		return nil, nil, err
+	}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		// Peel annotated tags to their commit.
+		hash := ref.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			commit, err := tag.Commit()
+			if err != nil {
+				return nil
+			}
+			hash = commit.Hash
+		}
+		tags[hash] = append(tags[hash], ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, names := range branches {
+		sort.Strings(names)
+	}
+	for _, names := range tags {
+		sort.Strings(names)
+	}
+
+	return branches, tags, nil
+}
+
+// commitQueue orders commits by committer date, oldest first.
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int { return len(q) }
+func (q commitQueue) Less(i, j int) bool {
+	if q[i].Committer.When.Equal(q[j].Committer.When) {
+		return q[i].Hash.String() < q[j].Hash.String()
+	}
+	return q[i].Committer.When.Before(q[j].Committer.When)
+}
+func (q commitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)   { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	commit := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return commit
+}
+
+// topoSort orders the commits oldest first, with every commit after its parents; commits
+// otherwise ordered by committer date.
+func topoSort(commits map[plumbing.Hash]*object.Commit) []*object.Commit {
+	// Count the parents within the graph, and note the children.
+	pending := map[plumbing.Hash]int{}
+	children := map[plumbing.Hash][]plumbing.Hash{}
+	for hash, commit := range commits {
+		for _, parent := range commit.ParentHashes {
+			if _, ok := commits[parent]; ok {
+				pending[hash]++
+				children[parent] = append(children[parent], hash)
+			}
+		}
+	}
+
+	queue := &commitQueue{}
+	for hash, commit := range commits {
+		if pending[hash] == 0 {
+			*queue = append(*queue, commit)
+		}
+	}
+	heap.Init(queue)
+
+	sorted := make([]*object.Commit, 0, len(commits))
+	for queue.Len() > 0 {
+		commit := heap.Pop(queue).(*object.Commit)
+		sorted = append(sorted, commit)
+		for _, child := range children[commit.Hash] {
+			pending[child]--
+			if pending[child] == 0 {
+				heap.Push(queue, commits[child])
+			}
+		}
+	}
+
+	return sorted
+}
+
+// newGitGraph builds the commit graph of the git log of the source. Each commit is assigned
+// a lane; the first-parent chain of the ref is lane 0, and every other chain starts at a
+// branch head or the merged parent of a merge commit.
+func newGitGraph(src *gitSource, processor cmn.GitProcessor) (cmn.GitGraph, error) {
+	funcName := "processors.newGitGraph"
+	cmn.Debug("%s: begin", funcName)
+
+	graph := cmn.GitGraph{Ref: src.info}
+
+	// Collect the commits; newest first.
+	commits := map[plumbing.Hash]*object.Commit{}
+	sources := map[plumbing.Hash]*gitSource{}
+	err := sourceLog(src, nil, processor, func(member *gitSource, commit *object.Commit) error {
+		commits[commit.Hash] = commit
+		sources[commit.Hash] = member
+		return nil
+	})
+	if err != nil {
+		return graph, err
+	}
+
+	// Decorate with the branches and tags of every repository of the source.
+	branches := map[plumbing.Hash][]string{}
+	tags := map[plumbing.Hash][]string{}
+	for _, member := range src.sources() {
+		memberBranches, memberTags, err := decorations(member.repo)
+		if err != nil {
+			return graph, err
+		}
+		for hash, names := range memberBranches {
+			branches[hash] = append(branches[hash], names...)
+		}
+		for hash, names := range memberTags {
+			tags[hash] = append(tags[hash], names...)
+		}
+	}
+
+	// Assign the lanes, following the first parents of each unassigned commit, newest first.
+	sorted := topoSort(commits)
+	lanes := map[plumbing.Hash]int{}
+	names := map[string]bool{}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if _, ok := lanes[sorted[i].Hash]; ok {
+			continue
+		}
+		lane := cmn.GitGraphLane{Index: len(graph.Lanes), Head: sorted[i].Hash.String()}
+		commit := sorted[i]
+		for {
+			lanes[commit.Hash] = lane.Index
+			if commit.NumParents() == 0 {
+				break
+			}
+			parent, ok := commits[commit.ParentHashes[0]]
+			if !ok {
+				break
+			}
+			if _, ok := lanes[parent.Hash]; ok {
+				lane.Fork = parent.Hash.String()
+				break
+			}
+			commit = parent
+		}
+		graph.Lanes = append(graph.Lanes, lane)
+	}
+
+	// Name the lanes; the ref, a branch at its head, or the branch named by the merge.
+	for _, commit := range sorted {
+		if commit.NumParents() < 2 {
+			continue
+		}
+		for _, parent := range commit.ParentHashes[1:] {
+			index, ok := lanes[parent]
+			if !ok || graph.Lanes[index].Head != parent.String() || graph.Lanes[index].Name != "" {
+				continue
+			}
+			if match := mergeBranch.FindStringSubmatch(commit.Message); match != nil {
+				graph.Lanes[index].Name = match[1]
+			}
+		}
+	}
+	for i := range graph.Lanes {
+		name := graph.Lanes[i].Name
+		head := plumbing.NewHash(graph.Lanes[i].Head)
+		switch {
+		case i == 0:
+			name = src.info.Short
+		case len(branches[head]) > 0:
+			name = branches[head][0]
+		}
+		if name == "" || names[name] {
+			name = fmt.Sprintf("lane-%d", i)
+		}
+		names[name] = true
+		graph.Lanes[i].Name = name
+	}
+
+	// Build the nodes and edges, oldest first.
+	for _, commit := range sorted {
+		node := cmn.GitGraphNode{
+			Hash:     commit.Hash.String(),
+			Short:    commit.Hash.String()[:7],
+			Lane:     lanes[commit.Hash],
+			Subject:  strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0],
+			Author:   sources[commit.Hash].mailmap.resolve(commit.Author.Name, commit.Author.Email),
+			Date:     commit.Committer.When,
+			Branches: branches[commit.Hash],
+			Tags:     tags[commit.Hash],
+			Merge:    commit.NumParents() > 1,
+		}
+		for i, parent := range commit.ParentHashes {
+			if _, ok := commits[parent]; !ok {
+				continue
+			}
+			node.Parents = append(node.Parents, parent.String())
+			graph.Edges = append(graph.Edges, cmn.GitGraphEdge{From: node.Hash, To: parent.String(), Merge: i > 0})
+		}
+		if node.Merge {
+			graph.Merges = append(graph.Merges, node.Hash)
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	graph.Mermaid = mermaidGraph(graph)
+	cmn.Debug("%s: %d nodes in %d lanes", funcName, len(graph.Nodes), len(graph.Lanes))
+
+	cmn.Debug("%s: end", funcName)
+	return graph, nil
+}
+
+// mermaidGraph renders the commit graph as a Mermaid gitGraph. Lanes become branches,
+// created at their fork commit; octopus merges only show their first merged parent.
+func mermaidGraph(graph cmn.GitGraph) string {
+	var b strings.Builder
+	if len(graph.Lanes) > 0 {
+		fmt.Fprintf(&b, "%%%%{init: { 'gitGraph': { 'mainBranchName': %q } } }%%%%\n", graph.Lanes[0].Name)
+	}
+	b.WriteString("gitGraph\n")
+
+	// Lanes forking from each commit.
+	forks := map[string][]int{}
+	for i := range graph.Lanes {
+		forks[graph.Lanes[i].Fork] = append(forks[graph.Lanes[i].Fork], i)
+	}
+	lanes := map[string]int{}
+	created := map[int]bool{0: true}
+	current := 0
+
+	for _, node := range graph.Nodes {
+		lanes[node.Hash] = node.Lane
+
+		// Lanes without a fork in the graph branch from wherever they first appear.
+		if !created[node.Lane] {
+			fmt.Fprintf(&b, "  branch %q\n", graph.Lanes[node.Lane].Name)
+			created[node.Lane] = true
+			current = node.Lane
+		}
+		if current != node.Lane {
+			fmt.Fprintf(&b, "  checkout %q\n", graph.Lanes[node.Lane].Name)
+			current = node.Lane
+		}
+
+		attrs := fmt.Sprintf("id: %q", node.Short)
+		if len(node.Tags) > 0 {
+			attrs += fmt.Sprintf(" tag: %q", node.Tags[0])
+		}
+		merged := -1
+		if node.Merge && len(node.Parents) > 1 {
+			if lane, ok := lanes[node.Parents[1]]; ok && lane != node.Lane {
+				merged = lane
+			}
+		}
+		if merged >= 0 {
+			fmt.Fprintf(&b, "  merge %q %s\n", graph.Lanes[merged].Name, attrs)
+		} else {
+			fmt.Fprintf(&b, "  commit %s\n", attrs)
+		}
+
+		// Create the lanes forking here, while this commit is the head of the current branch.
+		for _, lane := range forks[node.Hash] {
+			fmt.Fprintf(&b, "  branch %q\n", graph.Lanes[lane].Name)
+			fmt.Fprintf(&b, "  checkout %q\n", graph.Lanes[node.Lane].Name)
+			created[lane] = true
+		}
+	}
+
+	return b.String()
+}
+
+// gitGraph - Process Graph mode git log processor.
+func gitGraph(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitGraph"
+	cmn.Debug("%s: begin", funcName)
+
+	graph, err := newGitGraph(src, processor)
+	if err != nil {
+		return err
+	}
+
+	scr, err := gitScript(src, processor, "graph")
+	if err != nil {
+		return err
+	}
+
+	err = gitOutput(src, processor, scr, graph, map[string]tengo.Object{
+		"graph": gitGraphObject(graph),
+	})
+	if err != nil {
+		return err
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
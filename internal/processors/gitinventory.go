@@ -0,0 +1,365 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/binary"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// languages maps the file extensions to their languages.
+var languages = map[string]string{
+	".c":        "C",
+	".h":        "C",
+	".cc":       "C++",
+	".cpp":      "C++",
+	".cxx":      "C++",
+	".hh":       "C++",
+	".hpp":      "C++",
+	".cs":       "C#",
+	".css":      "CSS",
+	".scss":     "SCSS",
+	".sass":     "Sass",
+	".less":     "Less",
+	".go":       "Go",
+	".html":     "HTML",
+	".htm":      "HTML",
+	".java":     "Java",
+	".js":       "JavaScript",
+	".mjs":      "JavaScript",
+	".cjs":      "JavaScript",
+	".jsx":      "JavaScript",
+	".json":     "JSON",
+	".kt":       "Kotlin",
+	".kts":      "Kotlin",
+	".lua":      "Lua",
+	".md":       "Markdown",
+	".markdown": "Markdown",
+	".php":      "PHP",
+	".pl":       "Perl",
+	".pm":       "Perl",
+	".py":       "Python",
+	".rb":       "Ruby",
+	".rs":       "Rust",
+	".sh":       "Shell",
+	".bash":     "Shell",
+	".zsh":      "Shell",
+	".sql":      "SQL",
+	".svg":      "SVG",
+	".swift":    "Swift",
+	".tengo":    "Tengo",
+	".toml":     "TOML",
+	".ts":       "TypeScript",
+	".tsx":      "TypeScript",
+	".txt":      "Text",
+	".xml":      "XML",
+	".yaml":     "YAML",
+	".yml":      "YAML",
+}
+
+// languageNames maps the file names without a known extension to their languages.
+var languageNames = map[string]string{
+	"dockerfile":  "Dockerfile",
+	"makefile":    "Makefile",
+	"gnumakefile": "Makefile",
+	"go.mod":      "Go Module",
+	"go.sum":      "Go Module",
+}
+
+// fileLanguage detects the language of the file from its name; `Binary` or `Text` if unknown.
+func fileLanguage(name string, binary bool) string {
+	base := strings.ToLower(path.Base(name))
+	if language, ok := languageNames[base]; ok {
+		return language
+	}
+	if language, ok := languages[path.Ext(base)]; ok {
+		return language
+	}
+	if binary {
+		return "Binary"
+	}
+
+	return "Text"
+}
+
+// fileExtension returns the lower case extension of the file; `(none)` without one.
+func fileExtension(name string) string {
+	if ext := strings.ToLower(path.Ext(path.Base(name))); ext != "" {
+		return ext
+	}
+
+	return "(none)"
+}
+
+// countLines counts the lines of the content; a final line without a newline counts.
+func countLines(r io.Reader) (int, error) {
+	buf := make([]byte, 32*1024)
+	lines := 0
+	last := byte('\n')
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if last != '\n' {
+		lines++
+	}
+
+	return lines, nil
+}
+
+// inventoryFile measures the file of the commit; its size, lines and whether it is binary.
+// Git LFS files are measured from their local object, if fetched; otherwise they are
+// binary with the size of the pointer.
+func inventoryFile(src *gitSource, f *object.File) (cmn.GitInventoryFile, error) {
+	file := cmn.GitInventoryFile{Path: f.Name, Size: f.Size}
+
+	pointer, err := fileLFSPointer(f)
+	if err != nil {
+		return file, err
+	}
+	if pointer != nil {
+		file.Size = pointer.Size
+		file.LFS = true
+		name, err := lfsObject(src, pointer)
+		if err != nil {
+			return file, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if name == "" {
+			cmn.Debug("processors.inventoryFile: %s: git lfs object missing; counted as binary", f.Name)
+			file.Binary = true
+			return file, nil
+		}
+
+		blob, err := os.Open(name)
+		if err != nil {
+			return file, err
+		}
+		defer blob.Close()
+		file.Binary, err = binary.IsBinary(blob)
+		if err != nil || file.Binary {
+			return file, err
+		}
+		_, err = blob.Seek(0, io.SeekStart)
+		if err != nil {
+			return file, err
+		}
+		file.Lines, err = countLines(blob)
+		return file, err
+	}
+
+	file.Binary, err = f.IsBinary()
+	if err != nil || file.Binary {
+		return file, err
+	}
+	reader, err := f.Reader()
+	if err != nil {
+		return file, err
+	}
+	defer reader.Close()
+	file.Lines, err = countLines(reader)
+
+	return file, err
+}
+
+// newGitInventory builds the inventory of the files of the commit matching the `paths` of
+// the processor, grouped by extension or language. Symbolic links and submodules are skipped.
+func newGitInventory(src *gitSource, processor cmn.GitProcessor, commit *object.Commit) (cmn.GitInventory, error) {
+	funcName := "processors.newGitInventory"
+	cmn.Debug("%s: begin", funcName)
+
+	inventory := cmn.GitInventory{
+		GroupBy: strings.ToLower(processor.GroupBy),
+		Hash:    commit.Hash.String(),
+		Ref:     src.info,
+	}
+	if inventory.GroupBy == "" {
+		inventory.GroupBy = "extension"
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return inventory, err
+	}
+
+	groups := map[string]*cmn.GitInventoryGroup{}
+	var files []cmn.GitInventoryFile
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if f.Mode == filemode.Symlink {
+			return nil
+		}
+		if len(processor.Paths) > 0 {
+			matched := false
+			for i := range processor.Paths {
+				if matchGlob(processor.Paths[i], f.Name) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil
+			}
+		}
+
+		file, err := inventoryFile(src, f)
+		if err != nil {
+			return err
+		}
+		if inventory.GroupBy == "language" {
+			file.Group = fileLanguage(f.Name, file.Binary)
+		} else {
+			file.Group = fileExtension(f.Name)
+		}
+		files = append(files, file)
+
+		group, ok := groups[file.Group]
+		if !ok {
+			group = &cmn.GitInventoryGroup{Name: file.Group}
+			groups[file.Group] = group
+		}
+		group.Files++
+		group.Lines += file.Lines
+		group.Size += file.Size
+		inventory.Files++
+		inventory.Lines += file.Lines
+		inventory.Size += file.Size
+		if file.Binary {
+			group.Binary++
+			inventory.Binary++
+		} else {
+			inventory.Text++
+		}
+		return nil
+	})
+	if err != nil {
+		return inventory, err
+	}
+
+	// Order the groups by their lines, then size; the largest first.
+	for _, group := range groups {
+		inventory.Groups = append(inventory.Groups, *group)
+	}
+	sort.Slice(inventory.Groups, func(i, j int) bool {
+		if inventory.Groups[i].Lines != inventory.Groups[j].Lines {
+			return inventory.Groups[i].Lines > inventory.Groups[j].Lines
+		}
+		if inventory.Groups[i].Size != inventory.Groups[j].Size {
+			return inventory.Groups[i].Size > inventory.Groups[j].Size
+		}
+		return inventory.Groups[i].Name < inventory.Groups[j].Name
+	})
+
+	largest := processor.Largest
+	if largest == 0 {
+		largest = 10
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+	inventory.Largest = files[:min(largest, len(files))]
+	cmn.Debug("%s: %s: %d files; %d lines; %d bytes", funcName, commit.Hash.String()[:7], inventory.Files, inventory.Lines, inventory.Size)
+
+	cmn.Debug("%s: end", funcName)
+	return inventory, nil
+}
+
+// inventoryDelta compares the inventory with the base inventory; the groups of either,
+// ordered by name.
+func inventoryDelta(inventory, base cmn.GitInventory) *cmn.GitInventoryDelta {
+	delta := &cmn.GitInventoryDelta{
+		Files:  inventory.Files - base.Files,
+		Lines:  inventory.Lines - base.Lines,
+		Size:   inventory.Size - base.Size,
+		Text:   inventory.Text - base.Text,
+		Binary: inventory.Binary - base.Binary,
+	}
+
+	groups := map[string]*cmn.GitInventoryGroup{}
+	for i := range inventory.Groups {
+		group := inventory.Groups[i]
+		groups[group.Name] = &group
+	}
+	for _, b := range base.Groups {
+		group, ok := groups[b.Name]
+		if !ok {
+			group = &cmn.GitInventoryGroup{Name: b.Name}
+			groups[b.Name] = group
+		}
+		group.Files -= b.Files
+		group.Lines -= b.Lines
+		group.Size -= b.Size
+		group.Binary -= b.Binary
+	}
+	for _, group := range groups {
+		delta.Groups = append(delta.Groups, *group)
+	}
+	sort.Slice(delta.Groups, func(i, j int) bool {
+		return delta.Groups[i].Name < delta.Groups[j].Name
+	})
+
+	return delta
+}
+
+// gitInventory - Process Inventory mode git log processor.
+func gitInventory(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitInventory"
+	cmn.Debug("%s: begin", funcName)
+
+	commit, err := commitObject(src, src.ref.Hash())
+	if err != nil {
+		return err
+	}
+
+	inventory, err := newGitInventory(src, processor, commit)
+	if err != nil {
+		return err
+	}
+
+	// Compare with the inventory of the configured revision.
+	if len(processor.Compare) > 0 {
+		baseCommit, err := revisionCommit(src, processor.Compare)
+		if err != nil {
+			return err
+		}
+		base, err := newGitInventory(src, processor, baseCommit)
+		if err != nil {
+			return err
+		}
+		inventory.Base = &base
+		inventory.Delta = inventoryDelta(inventory, base)
+		cmn.Debug("%s: compared with %s: %d files; %d lines", funcName, processor.Compare, inventory.Delta.Files, inventory.Delta.Lines)
+	}
+
+	scr, err := gitScript(src, processor, "inventory")
+	if err != nil {
+		return err
+	}
+
+	err = gitOutput(src, processor, scr, inventory, map[string]tengo.Object{
+		"inventory": gitInventoryObject(inventory),
+	})
+	if err != nil {
+		return err
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
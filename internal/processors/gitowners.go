@@ -0,0 +1,323 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// codeownersPaths are the locations of the CODEOWNERS file, in the order GitHub and GitLab
+// look for it.
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// codeownersSection matches a GitLab section header; e.g. `^[Docs][2] @docs-team`.
+var codeownersSection = regexp.MustCompile(`^(\^)?\[([^\]]+)\](?:\[([0-9]+)\])?(.*)$`)
+
+// ownersRule is a pattern of a CODEOWNERS file and its owners.
+type ownersRule struct {
+	pattern string
+	match   *regexp.Regexp
+	line    int
+	owners  []string
+}
+
+// ownersSection is a section of a CODEOWNERS file; files without sections have a single
+// unnamed section, as GitHub does.
+type ownersSection struct {
+	name      string
+	optional  bool
+	approvals int
+	owners    []string
+	rules     []ownersRule
+}
+
+// codeownersPattern compiles a CODEOWNERS pattern, with the gitignore syntax; a leading or
+// inner `/` anchors the pattern to the root, a trailing `/` matches only the contents of a
+// directory, `*` and `?` do not match `/`, and `**` matches any number of directories.
+// A pattern naming a file or directory also matches the files beneath it; one whose last
+// segment has a wildcard does not, e.g. `docs/*` matches `docs/a.md` but not `docs/b/c.md`,
+// unless it ends in `**`.
+func codeownersPattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	descendants := !codeownersWildcard(last) || strings.HasSuffix(last, "**")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case descendants:
+		b.WriteString("(?:/.*)?$")
+	default:
+		b.WriteString("$")
+	}
+
+	return regexp.Compile(b.String())
+}
+
+// codeownersWildcard reports whether the pattern segment has an unescaped `*` or `?`.
+func codeownersWildcard(segment string) bool {
+	for i := 0; i < len(segment); i++ {
+		switch segment[i] {
+		case '\\':
+			i++
+		case '*', '?':
+			return true
+		}
+	}
+
+	return false
+}
+
+// codeownersFields splits a CODEOWNERS line into its fields, keeping escaped spaces and
+// `#` in the pattern, and dropping a trailing comment.
+func codeownersFields(line string) []string {
+	var fields []string
+	var field strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line) && (line[i+1] == ' ' || line[i+1] == '#'):
+			i++
+			field.WriteByte(line[i])
+		case c == ' ' || c == '\t':
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		case c == '#' && field.Len() == 0:
+			return fields
+		default:
+			field.WriteByte(c)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+
+	return fields
+}
+
+// parseCodeowners parses a CODEOWNERS file, with GitHub or GitLab syntax, into its sections.
+// Sections of the same name, ignoring case, are combined as GitLab does.
+func parseCodeowners(content string) ([]*ownersSection, error) {
+	sections := []*ownersSection{{}}
+	byName := map[string]*ownersSection{}
+	section := sections[0]
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if match := codeownersSection.FindStringSubmatch(line); match != nil {
+			name := strings.TrimSpace(match[2])
+			key := strings.ToLower(name)
+			var ok bool
+			section, ok = byName[key]
+			if !ok {
+				section = &ownersSection{name: name}
+				byName[key] = section
+				sections = append(sections, section)
+			}
+			section.optional = match[1] == "^"
+			if match[3] != "" {
+				section.approvals, _ = strconv.Atoi(match[3])
+			}
+			section.owners = codeownersFields(match[4])
+			continue
+		}
+
+		fields := codeownersFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		match, err := codeownersPattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CODEOWNERS pattern: line %d: %s: %w", number, fields[0], err)
+		}
+		section.rules = append(section.rules, ownersRule{pattern: fields[0], match: match, line: number, owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+// resolveOwners resolves the owners of the repository path; the last matching rule of each
+// section wins, a rule without owners taking the default owners of its section. The owners
+// of all sections are combined, in order.
+func resolveOwners(sections []*ownersSection, path string) cmn.GitOwnersEntry {
+	entry := cmn.GitOwnersEntry{Owners: []string{}}
+	seen := map[string]bool{}
+	for _, section := range sections {
+		for i := len(section.rules) - 1; i >= 0; i-- {
+			rule := section.rules[i]
+			if !rule.match.MatchString(path) {
+				continue
+			}
+
+			owners := rule.owners
+			if len(owners) == 0 && section.name != "" {
+				owners = section.owners
+			}
+			entry.Sections = append(entry.Sections, cmn.GitOwnersSection{
+				Name:      section.name,
+				Optional:  section.optional,
+				Approvals: section.approvals,
+				Pattern:   rule.pattern,
+				Line:      rule.line,
+				Owners:    owners,
+			})
+			for _, owner := range owners {
+				if !seen[strings.ToLower(owner)] {
+					seen[strings.ToLower(owner)] = true
+					entry.Owners = append(entry.Owners, owner)
+				}
+			}
+			break
+		}
+	}
+
+	return entry
+}
+
+// newGitOwners resolves the owners of the files matching the processor and tracked in the
+// commit, per the CODEOWNERS file of the commit.
+func newGitOwners(src *gitSource, processor cmn.GitProcessor, commit *object.Commit) (cmn.GitOwners, error) {
+	funcName := "processors.newGitOwners"
+	cmn.Debug("%s: begin", funcName)
+
+	owners := cmn.GitOwners{Owners: map[string][]string{}, Ref: src.info}
+
+	// Find the CODEOWNERS file.
+	var content string
+	for _, name := range codeownersPaths {
+		file, err := commit.File(name)
+		if err == object.ErrFileNotFound {
+			continue
+		}
+		if err != nil {
+			return owners, err
+		}
+		content, err = file.Contents()
+		if err != nil {
+			return owners, err
+		}
+		owners.File = name
+		break
+	}
+	if owners.File == "" {
+		cmn.Debug("%s: no CODEOWNERS file; all files unowned", funcName)
+	} else {
+		cmn.Debug("%s: CODEOWNERS file: %s", funcName, owners.File)
+	}
+
+	sections, err := parseCodeowners(content)
+	if err != nil {
+		return owners, err
+	}
+
+	files, err := processorFiles(processor)
+	if err != nil {
+		return owners, err
+	}
+	files, err = trackedFiles(src, processor, files)
+	if err != nil {
+		return owners, err
+	}
+	for i := range files {
+		relPath, err := repoRelPath(src.repo, files[i])
+		if err != nil {
+			return owners, err
+		}
+
+		entry := resolveOwners(sections, relPath)
+		entry.Path = files[i]
+		owners.Files = append(owners.Files, entry)
+		owners.Owners[entry.Path] = entry.Owners
+		if len(entry.Owners) == 0 {
+			owners.Unowned = append(owners.Unowned, entry.Path)
+		}
+	}
+	sort.Slice(owners.Files, func(i, j int) bool {
+		return owners.Files[i].Path < owners.Files[j].Path
+	})
+	sort.Strings(owners.Unowned)
+	cmn.Debug("%s: %d files; %d unowned", funcName, len(owners.Files), len(owners.Unowned))
+
+	cmn.Debug("%s: end", funcName)
+	return owners, nil
+}
+
+// gitOwners - Process Owners mode git log processor.
+func gitOwners(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitOwners"
+	cmn.Debug("%s: begin", funcName)
+
+	commit, err := commitObject(src, src.ref.Hash())
+	if err != nil {
+		return err
+	}
+
+	owners, err := newGitOwners(src, processor, commit)
+	if err != nil {
+		return err
+	}
+	if len(owners.Files) == 0 && src.info.Repo != "" {
+		cmn.Debug("%s: no matching files in repo %s, skipping", funcName, src.info.Repo)
+		cmn.Debug("%s: end", funcName)
+		return nil
+	}
+
+	scr, err := gitScript(src, processor, "owners")
+	if err != nil {
+		return err
+	}
+
+	err = gitOutput(src, processor, scr, owners, map[string]tengo.Object{
+		"owners": gitOwnersObject(owners),
+	})
+	if err != nil {
+		return err
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
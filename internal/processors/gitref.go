@@ -0,0 +1,215 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// gitSource is a repository opened by a git handler, and the ref being processed; the
+// hash of the ref is always that of a commit.
+type gitSource struct {
+	repo    *git.Repository
+	ref     *plumbing.Reference
+	info    cmn.GitRef
+	state   *gitState
+	mailmap *mailmap
+	keyring *keyring
+	// worktree is the status of the working tree; only for the checked out commit.
+	worktree *worktree
+	// shallow is the truncated history of a shallow repository.
+	shallow *shallowHistory
+	// references are the issue and ticket reference patterns.
+	references []referencePattern
+	// members are the sources of the repositories of a merged source.
+	members []*gitSource
+}
+
+// newGitSource builds the source for the ref, peeling annotated tags to their commit.
+func newGitSource(repo *git.Repository, state *gitState, mm *mailmap, kr *keyring, ref *plumbing.Reference) (*gitSource, error) {
+	hash := ref.Hash()
+	for {
+		tag, err := repo.TagObject(hash)
+		if err == plumbing.ErrObjectNotFound {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		hash = tag.Target
+	}
+
+	refType := "commit"
+	switch {
+	case ref.Name() == plumbing.HEAD:
+		refType = "head"
+	case ref.Name().IsBranch():
+		refType = "branch"
+	case ref.Name().IsTag():
+		refType = "tag"
+	case ref.Name().IsRemote():
+		refType = "remote"
+	}
+
+	return &gitSource{
+		repo:    repo,
+		state:   state,
+		mailmap: mm,
+		keyring: kr,
+		ref:     plumbing.NewHashReference(ref.Name(), hash),
+		info: cmn.GitRef{
+			Name:  ref.Name().String(),
+			Short: ref.Name().Short(),
+			Type:  refType,
+			Hash:  hash.String(),
+		},
+	}, nil
+}
+
+// resolveRef resolves the configured ref; a branch, tag, remote branch or other reference
+// name, or any revision expression such as a hash or `main~3`. An empty ref is the HEAD.
+func resolveRef(repo *git.Repository, name string) (*plumbing.Reference, error) {
+	funcName := "processors.resolveRef"
+	cmn.Debug("%s: begin", funcName)
+
+	if name == "" {
+		cmn.Debug("%s: end", funcName)
+		return repo.Head()
+	}
+
+	// Expand the name as git does.
+	for _, rule := range plumbing.RefRevParseRules {
+		ref, err := repo.Reference(plumbing.ReferenceName(fmt.Sprintf(rule, name)), true)
+		if err == nil {
+			cmn.Debug("%s: %s: reference: %s", funcName, name, ref.Name().String())
+			cmn.Debug("%s: end", funcName)
+			return ref, nil
+		}
+	}
+
+	// Otherwise a revision expression.
+	hash, err := repo.ResolveRevision(plumbing.Revision(name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve ref: %s: %w", name, err)
+	}
+	cmn.Debug("%s: %s: revision: %s", funcName, name, hash.String())
+
+	cmn.Debug("%s: end", funcName)
+	return plumbing.NewHashReference(plumbing.ReferenceName(name), *hash), nil
+}
+
+// matchRefs lists the references whose full or short names match any of the globs,
+// sorted by name.
+func matchRefs(repo *git.Repository, globs []string) ([]*plumbing.Reference, error) {
+	funcName := "processors.matchRefs"
+	cmn.Debug("%s: begin", funcName)
+
+	refIter, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refIter.Close()
+
+	var refs []*plumbing.Reference
+	err = refIter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		for i := range globs {
+			if matchGlob(globs[i], ref.Name().String()) || matchGlob(globs[i], ref.Name().Short()) {
+				refs = append(refs, ref)
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil && err != storer.ErrStop {
+		return nil, err
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Name() < refs[j].Name()
+	})
+	cmn.Debug("%s: matched %d refs", funcName, len(refs))
+
+	cmn.Debug("%s: end", funcName)
+	return refs, nil
+}
+
+// gitSources resolves the refs configured for the git handler into sources.
+func gitSources(repo *git.Repository, state *gitState, config cmn.Git) ([]*gitSource, error) {
+	mm, err := loadMailmap(repo, config.Mailmap)
+	if err != nil {
+		return nil, err
+	}
+	kr, err := loadKeyring(config.Keyring)
+	if err != nil {
+		return nil, err
+	}
+	shallow, err := loadShallow(repo, config)
+	if err != nil {
+		return nil, err
+	}
+	references, err := loadReferences(config.References)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*plumbing.Reference
+	if len(config.Refs) > 0 {
+		var err error
+		refs, err = matchRefs(repo, config.Refs)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ref, err := resolveRef(repo, config.Ref)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	// The working tree status; read only when used, as it hashes the changed files.
+	var wt *worktree
+	var head *plumbing.Reference
+	if config.Worktree || workingCopy(config) {
+		wt, err = loadWorktree(repo)
+		if err != nil {
+			return nil, err
+		}
+		head, err = repo.Head()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sources := make([]*gitSource, 0, len(refs))
+	for i := range refs {
+		src, err := newGitSource(repo, state, mm, kr, refs[i])
+		if err != nil {
+			return nil, err
+		}
+		src.shallow = shallow
+		src.references = references
+		if shallow != nil {
+			src.info.Shallow = true
+			for hash := range shallow.boundary {
+				src.info.Boundary = append(src.info.Boundary, hash.String())
+			}
+			sort.Strings(src.info.Boundary)
+		}
+		if wt != nil && src.ref.Hash() == head.Hash() {
+			src.worktree = wt
+			src.info.Worktree = wt.status
+		}
+		sources = append(sources, src)
+	}
+
+	return sources, nil
+}
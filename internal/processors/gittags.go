@@ -0,0 +1,195 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// errTagTarget is returned for tags of trees or blobs; only tags of commits have a history.
+var errTagTarget = errors.New("tag target is not a commit")
+
+// versionTag is a tag, and its semantic version; nil if the name is not a version.
+type versionTag struct {
+	ref     *plumbing.Reference
+	version *semver.Version
+}
+
+// versionTags lists the tags of the repository; those with semantic version names sorted by
+// version, followed by the others sorted by name.
+func versionTags(repo *git.Repository) ([]versionTag, error) {
+	funcName := "processors.versionTags"
+	cmn.Debug("%s: begin", funcName)
+
+	tagIter, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	defer tagIter.Close()
+
+	var tags []versionTag
+	err = tagIter.ForEach(func(ref *plumbing.Reference) error {
+		version, err := semver.NewVersion(ref.Name().Short())
+		if err != nil {
+			cmn.Debug("%s: non-version tag: %s", funcName, ref.Name().Short())
+			version = nil
+		}
+		tags = append(tags, versionTag{ref: ref, version: version})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		switch {
+		case tags[i].version != nil && tags[j].version != nil:
+			return tags[i].version.LessThan(tags[j].version)
+		case tags[i].version != nil || tags[j].version != nil:
+			return tags[i].version != nil
+		}
+		return tags[i].ref.Name() < tags[j].ref.Name()
+	})
+	cmn.Debug("%s: found %d tags", funcName, len(tags))
+
+	cmn.Debug("%s: end", funcName)
+	return tags, nil
+}
+
+// newGitTag builds the tag data for the tag reference, resolving annotated tags to their
+// tagger, message and target commit; peeling tags of tags.
+func newGitTag(src *gitSource, processor cmn.GitProcessor, tag versionTag) (cmn.GitTag, *object.Commit, error) {
+	gitTag := cmn.GitTag{
+		Name: tag.ref.Name().Short(),
+		Ref:  src.info,
+	}
+	if tag.version != nil {
+		gitTag.Version = tag.version.String()
+	}
+
+	var commit *object.Commit
+	tagObject, err := src.repo.TagObject(tag.ref.Hash())
+	switch err {
+	case nil:
+		gitTag.Annotated = true
+		gitTag.Tagger = tagObject.Tagger
+		gitTag.Message = tagObject.Message
+		gitTag.Signature, err = verifySignature(src.keyring, tagObject.PGPSignature, tagObject.EncodeWithoutSignature)
+		if err != nil {
+			return gitTag, nil, err
+		}
+		target := tagObject
+		for target.TargetType == plumbing.TagObject {
+			target, err = src.repo.TagObject(target.Target)
+			if err != nil {
+				return gitTag, nil, err
+			}
+		}
+		if target.TargetType != plumbing.CommitObject {
+			return gitTag, nil, errTagTarget
+		}
+		commit, err = src.repo.CommitObject(target.Target)
+		if err != nil {
+			return gitTag, nil, err
+		}
+	case plumbing.ErrObjectNotFound:
+		obj, err := src.repo.Storer.EncodedObject(plumbing.AnyObject, tag.ref.Hash())
+		if err != nil {
+			return gitTag, nil, err
+		}
+		if obj.Type() != plumbing.CommitObject {
+			return gitTag, nil, errTagTarget
+		}
+		commit, err = src.repo.CommitObject(tag.ref.Hash())
+		if err != nil {
+			return gitTag, nil, err
+		}
+	default:
+		return gitTag, nil, err
+	}
+
+	gitTag.Target, err = newGitLogEntry(src, processor, commit)
+	if err != nil {
+		return gitTag, nil, err
+	}
+
+	return gitTag, commit, nil
+}
+
+// gitTags - Process Tags mode git log processor.
+func gitTags(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitTags"
+	cmn.Debug("%s: begin", funcName)
+
+	tags, err := versionTags(src.repo)
+	if err != nil {
+		return err
+	}
+
+	scr, err := gitScript(src, processor, "tag")
+	if err != nil {
+		return err
+	}
+
+	// Commits reachable from the tags processed so far; the history of each tag stops at them.
+	seen := map[plumbing.Hash]bool{}
+	previous := ""
+	for i := range tags {
+		gitTag, commit, err := newGitTag(src, processor, tags[i])
+		if err == plumbing.ErrObjectNotFound && src.shallow != nil {
+			cmn.Debug("%s: tag %s: beyond the shallow history, skipping", funcName, tags[i].ref.Name().Short())
+			continue
+		}
+		if err == errTagTarget {
+			cmn.Debug("%s: tag %s: not a tag of a commit, skipping", funcName, tags[i].ref.Name().Short())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		gitTag.Previous = previous
+		cmn.Debug("%s: tag %s: commit %s", funcName, gitTag.Name, commit.Hash.String()[0:7])
+
+		// Grab the commits since the previous tag.
+		err = gitLog(src, commit.Hash, seen, processor, func(c *object.Commit) error {
+			entry, err := newGitLogEntry(src, processor, c)
+			if err != nil {
+				return err
+			}
+			gitTag.Commits = append(gitTag.Commits, entry)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		cmn.Debug("%s: tag %s: %d commits since previous tag", funcName, gitTag.Name, len(gitTag.Commits))
+		gitTag.Groups = groupByType(gitTag.Commits)
+
+		err = gitOutput(src, processor, scr, gitTag, map[string]tengo.Object{
+			"tag": gitTagObject(gitTag),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Mark the history of the tag as seen.
+		err = object.NewCommitPreorderIter(commit, seen, src.shallow.ignore()).ForEach(func(c *object.Commit) error {
+			seen[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		previous = gitTag.Name
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
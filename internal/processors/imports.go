@@ -0,0 +1,197 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// importedFile is a file copied by an import handler, as recorded in the provenance file.
+type importedFile struct {
+	Source string `json:"source" yaml:"source" toml:"source"`
+	Target string `json:"target" yaml:"target" toml:"target"`
+	Hash   string `json:"hash" yaml:"hash" toml:"hash"`
+}
+
+// provenance records the source of the files imported by an import handler.
+type provenance struct {
+	Repo    string         `json:"repo" yaml:"repo" toml:"repo"`
+	Ref     string         `json:"ref" yaml:"ref" toml:"ref"`
+	Commit  string         `json:"commit" yaml:"commit" toml:"commit"`
+	Date    time.Time      `json:"date" yaml:"date" toml:"date"`
+	Subject string         `json:"subject" yaml:"subject" toml:"subject"`
+	Files   []importedFile `json:"files" yaml:"files" toml:"files"`
+}
+
+// importTarget rewrites the repository path of an imported file into its path beneath the
+// target directory; the rewrites apply in order, and the result may not leave the target.
+func importTarget(rewrites []*regexp.Regexp, config cmn.Import, name string) (string, error) {
+	rewritten := name
+	for i := range rewrites {
+		rewritten = rewrites[i].ReplaceAllString(rewritten, config.Rewrites[i].To)
+	}
+
+	rel := filepath.FromSlash(strings.TrimPrefix(path.Clean(rewritten), "/"))
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("import path rewritten outside of target: %s: %s", name, rewritten)
+	}
+
+	return filepath.Join(config.Target, rel), nil
+}
+
+// writeProvenance writes the provenance file; as JSON, YAML or TOML per its extension.
+func writeProvenance(name string, prov provenance) error {
+	var content []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		content, err = json.MarshalIndent(prov, "", "  ")
+		content = append(content, '\n')
+	case ".yaml", ".yml":
+		content, err = yaml.Marshal(prov)
+	case ".toml":
+		content, err = toml.Marshal(prov)
+	default:
+		return fmt.Errorf("invalid provenance format: %s", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeFile(name, string(content))
+}
+
+// importFiles copies the matching files of the commit of the configured ref into the
+// target directory. The files are read from the object store; the working tree of the
+// repository is never read nor changed.
+func importFiles(config cmn.Import) error {
+	funcName := "processors.importFiles"
+	cmn.Debug("%s: begin", funcName)
+
+	repo, err := openRepo(config.Path)
+	if err != nil {
+		return err
+	}
+	ref, err := resolveRef(repo, config.Ref)
+	if err != nil {
+		return err
+	}
+	src, err := newGitSource(repo, nil, nil, nil, ref)
+	if err != nil {
+		return err
+	}
+	commit, err := repo.CommitObject(src.ref.Hash())
+	if err != nil {
+		return err
+	}
+	cmn.Debug("%s: ref: %s: commit: %s", funcName, src.info.Name, src.info.Hash)
+
+	rewrites := make([]*regexp.Regexp, 0, len(config.Rewrites))
+	for i := range config.Rewrites {
+		rewrite, err := regexp.Compile(config.Rewrites[i].From)
+		if err != nil {
+			return err
+		}
+		rewrites = append(rewrites, rewrite)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+
+	prov := provenance{
+		Repo:    config.Path,
+		Ref:     src.info.Short,
+		Commit:  src.info.Hash,
+		Date:    commit.Committer.When,
+		Subject: strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0],
+	}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		matched := len(config.Paths) == 0
+		for i := range config.Paths {
+			if matchGlob(config.Paths[i], f.Name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil
+		}
+		if f.Mode == filemode.Symlink {
+			cmn.Debug("%s: %s: symbolic link, skipping", funcName, f.Name)
+			return nil
+		}
+
+		target, err := importTarget(rewrites, config, f.Name)
+		if err != nil {
+			return err
+		}
+		content, _, err := readGitBlob(src, f)
+		if err != nil {
+			return err
+		}
+		err = writeFile(target, content)
+		if err != nil {
+			return err
+		}
+		// Keep the executable bit of the tree entry; also of a file imported before.
+		perm := os.FileMode(0644)
+		if f.Mode == filemode.Executable {
+			perm = 0755
+		}
+		err = os.Chmod(target, perm)
+		if err != nil {
+			return err
+		}
+		cmn.Debug("%s: %s: imported to %s; mode %v", funcName, f.Name, target, perm)
+
+		prov.Files = append(prov.Files, importedFile{Source: f.Name, Target: filepath.ToSlash(target), Hash: f.Hash.String()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cmn.Debug("%s: imported %d files", funcName, len(prov.Files))
+
+	if len(config.Provenance) > 0 {
+		err = writeProvenance(config.Provenance, prov)
+		if err != nil {
+			return err
+		}
+		cmn.Debug("%s: wrote provenance: %s", funcName, config.Provenance)
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
+
+// Imports - Process the configured import handlers.
+func Imports(configs *cmn.Configs) error {
+	funcName := "processors.Imports"
+	cmn.Debug("%s: begin", funcName)
+
+	cmn.Debug("%s: iterating imports: %d", funcName, len(configs.Imports))
+	for i := range configs.Imports {
+		cmn.Debug("%s: import %d: path: %s: ref: %s", funcName, i, configs.Imports[i].Path, configs.Imports[i].Ref)
+		err := importFiles(configs.Imports[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
@@ -0,0 +1,161 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// lfsPointerMaxSize is the largest size of a Git LFS pointer file.
+const lfsPointerMaxSize = 1024
+
+// lfsVersions are the spec versions of Git LFS pointer files.
+var lfsVersions = []string{"https://git-lfs.github.com/spec/v1", "https://hawser.github.com/spec/v1"}
+
+// lfsOID matches the object ID of a Git LFS pointer.
+var lfsOID = regexp.MustCompile(`^sha256:([0-9a-f]{64})$`)
+
+// parseLFSPointer parses the content of a Git LFS pointer file; nil if the content is not a pointer.
+func parseLFSPointer(content []byte) *cmn.GitLFSPointer {
+	if len(content) >= lfsPointerMaxSize {
+		return nil
+	}
+
+	var pointer cmn.GitLFSPointer
+	version := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			return nil
+		}
+		switch key {
+		case "version":
+			for i := range lfsVersions {
+				version = version || value == lfsVersions[i]
+			}
+			if !version {
+				return nil
+			}
+		case "oid":
+			match := lfsOID.FindStringSubmatch(value)
+			if match == nil {
+				return nil
+			}
+			pointer.OID = match[1]
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil
+			}
+			pointer.Size = size
+		}
+	}
+	if !version || pointer.OID == "" {
+		return nil
+	}
+
+	return &pointer
+}
+
+// fileLFSPointer reads the Git LFS pointer of the file; nil if the file is not a pointer.
+func fileLFSPointer(file *object.File) (*cmn.GitLFSPointer, error) {
+	if file.Size >= lfsPointerMaxSize {
+		return nil, nil
+	}
+
+	reader, err := file.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLFSPointer(content), nil
+}
+
+// lfsObjectPath returns the path of the Git LFS object in the local store of the repository;
+// `lfs/objects` of the git directory, shared by linked worktrees.
+func lfsObjectPath(src *gitSource, oid string) (string, error) {
+	storage, ok := src.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", fmt.Errorf("no local git lfs store")
+	}
+
+	dir := storage.Filesystem().Root()
+	if common, err := os.ReadFile(filepath.Join(dir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(dir, common)
+		}
+		dir = common
+	}
+
+	return filepath.Join(dir, "lfs", "objects", oid[0:2], oid[2:4], oid), nil
+}
+
+// lfsObject finds the Git LFS object of the pointer in the local store of the repository;
+// of any member repository of a merged source. An empty path is returned if missing.
+func lfsObject(src *gitSource, pointer *cmn.GitLFSPointer) (string, error) {
+	for _, member := range src.sources() {
+		name, err := lfsObjectPath(member, pointer.OID)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Size() != pointer.Size {
+			return "", fmt.Errorf("git lfs object size mismatch: oid %s: %d bytes; expected %d", pointer.OID, info.Size(), pointer.Size)
+		}
+		return name, nil
+	}
+
+	return "", nil
+}
+
+// readGitBlob reads the content of the file, resolving Git LFS pointers from the local
+// store of the repository. The pointer is nil if the file is not a Git LFS pointer.
+func readGitBlob(src *gitSource, file *object.File) (string, *cmn.GitLFSPointer, error) {
+	content, err := file.Contents()
+	if err != nil {
+		return "", nil, err
+	}
+	pointer := parseLFSPointer([]byte(content))
+	if pointer == nil {
+		return content, nil, nil
+	}
+
+	name, err := lfsObject(src, pointer)
+	if err != nil {
+		return "", pointer, fmt.Errorf("%s: %w", file.Name, err)
+	}
+	if name == "" {
+		return "", pointer, fmt.Errorf("git lfs object missing: %s: oid %s; fetch it with `git lfs fetch`", file.Name, pointer.OID)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", pointer, err
+	}
+	cmn.Debug("processors.readGitBlob: %s: git lfs object: %s", file.Name, name)
+
+	return string(data), pointer, nil
+}
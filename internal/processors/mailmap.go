@@ -0,0 +1,150 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// mailmapEmail matches an email of a .mailmap entry; `<email>`.
+var mailmapEmail = regexp.MustCompile(`<([^>]*)>`)
+
+// mailmapEntry maps a commit identity to the proper identity.
+type mailmapEntry struct {
+	properName  string
+	properEmail string
+	commitName  string
+	commitEmail string
+}
+
+// mailmap maps commit identities to the canonical identities of a .mailmap file.
+type mailmap struct {
+	entries []mailmapEntry
+}
+
+// parseMailmap parses the content of a .mailmap file.
+func parseMailmap(data string) *mailmap {
+	m := &mailmap{}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Entries are `Proper Name <proper@email> Commit Name <commit@email>`, where either
+		// name may be omitted; with a single email, it is the commit email.
+		emails := mailmapEmail.FindAllStringSubmatchIndex(line, 2)
+		if emails == nil {
+			continue
+		}
+		entry := mailmapEntry{properName: strings.TrimSpace(line[:emails[0][0]])}
+		if len(emails) == 1 {
+			entry.commitEmail = strings.TrimSpace(line[emails[0][2]:emails[0][3]])
+		} else {
+			entry.properEmail = strings.TrimSpace(line[emails[0][2]:emails[0][3]])
+			entry.commitName = strings.TrimSpace(line[emails[0][1]:emails[1][0]])
+			entry.commitEmail = strings.TrimSpace(line[emails[1][2]:emails[1][3]])
+		}
+		m.entries = append(m.entries, entry)
+	}
+
+	return m
+}
+
+// resolve returns the canonical identity of the commit name and email; entries matching
+// both the name and email take precedence over those matching the email only, and later
+// entries over earlier ones.
+func (m *mailmap) resolve(name, email string) cmn.GitAuthor {
+	identity := cmn.GitAuthor{Name: name, Email: email}
+	if m == nil {
+		return identity
+	}
+
+	var found *mailmapEntry
+	for i := range m.entries {
+		entry := &m.entries[i]
+		if !strings.EqualFold(entry.commitEmail, email) {
+			continue
+		}
+		if entry.commitName != "" && !strings.EqualFold(entry.commitName, name) {
+			continue
+		}
+		if found != nil && found.commitName != "" && entry.commitName == "" {
+			continue
+		}
+		found = entry
+	}
+
+	if found != nil {
+		if found.properName != "" {
+			identity.Name = found.properName
+		}
+		if found.properEmail != "" {
+			identity.Email = found.properEmail
+		}
+	}
+
+	return identity
+}
+
+// loadMailmap reads the configured .mailmap file; otherwise the .mailmap of the repository
+// worktree, or of the HEAD tree of a bare repository. No mailmap is returned if none exists.
+func loadMailmap(repo *git.Repository, path string) (*mailmap, error) {
+	funcName := "processors.loadMailmap"
+	cmn.Debug("%s: begin", funcName)
+
+	if path != "" {
+		cmn.Debug("%s: reading configured mailmap: %s", funcName, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return parseMailmap(string(data)), nil
+	}
+
+	// The .mailmap of the worktree.
+	worktree, err := repo.Worktree()
+	if err == nil {
+		data, err := os.ReadFile(filepath.Join(worktree.Filesystem.Root(), ".mailmap"))
+		if os.IsNotExist(err) {
+			cmn.Debug("%s: no mailmap", funcName)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		cmn.Debug("%s: reading worktree mailmap", funcName)
+		return parseMailmap(string(data)), nil
+	}
+	if err != git.ErrIsBareRepository {
+		return nil, err
+	}
+
+	// The .mailmap of the HEAD tree of a bare repository.
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	file, err := commit.File(".mailmap")
+	if err != nil {
+		cmn.Debug("%s: no mailmap", funcName)
+		return nil, nil
+	}
+	data, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+	cmn.Debug("%s: reading head tree mailmap", funcName)
+
+	cmn.Debug("%s: end", funcName)
+	return parseMailmap(data), nil
+}
@@ -0,0 +1,196 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// openRepo opens the repository at the path; the current directory if empty.
+func openRepo(path string) (*git.Repository, error) {
+	funcName := "processors.openRepo"
+	cmn.Debug("%s: begin", funcName)
+
+	if path == "" {
+		path = "."
+	}
+	cmn.Debug("%s: repo path: %s", funcName, path)
+
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err == git.ErrRepositoryNotExists {
+		// Detecting the .git directory fails for bare repositories.
+		repo, err = git.PlainOpen(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return repo, nil
+}
+
+// handlerSources opens the repository of the git handler and resolves its sources. The
+// repositories listed in `repos` are merged into a single source, with each repository
+// as a member source labelled with its repo.
+func handlerSources(state *gitState, config cmn.Git) ([]*gitSource, error) {
+	funcName := "processors.handlerSources"
+	cmn.Debug("%s: begin", funcName)
+
+	if len(config.Repos) == 0 {
+		repo, err := openRepo(config.Path)
+		if err != nil {
+			return nil, err
+		}
+		return gitSources(repo, state, config)
+	}
+
+	references, err := loadReferences(config.References)
+	if err != nil {
+		return nil, err
+	}
+	merged := &gitSource{
+		state:      state,
+		info:       cmn.GitRef{Name: "merged", Short: "merged", Type: "merged"},
+		references: references,
+	}
+	for i := range config.Repos {
+		repo, err := openRepo(config.Repos[i].Path)
+		if err != nil {
+			return nil, err
+		}
+
+		member := config
+		member.Repos = nil
+		member.Path = config.Repos[i].Path
+		if config.Repos[i].Ref != "" {
+			member.Ref = config.Repos[i].Ref
+		}
+		sources, err := gitSources(repo, state, member)
+		if err != nil {
+			return nil, err
+		}
+
+		label := config.Repos[i].Label
+		if label == "" {
+			abs, err := filepath.Abs(config.Repos[i].Path)
+			if err != nil {
+				return nil, err
+			}
+			label = filepath.Base(abs)
+		}
+		sources[0].info.Repo = label
+		cmn.Debug("%s: repo %s: ref: %s", funcName, label, sources[0].info.Name)
+		merged.members = append(merged.members, sources[0])
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return []*gitSource{merged}, nil
+}
+
+// sources returns the member sources of a merged source, or the source itself.
+func (s *gitSource) sources() []*gitSource {
+	if len(s.members) > 0 {
+		return s.members
+	}
+
+	return []*gitSource{s}
+}
+
+// inWorktree reports whether the file is within the worktree of the repository of a
+// member source; files of the other repositories of a merged source are skipped.
+func inWorktree(src *gitSource, path string) (bool, error) {
+	if src.info.Repo == "" {
+		return true, nil
+	}
+
+	relPath, err := repoRelPath(src.repo, path)
+	if err != nil {
+		return false, err
+	}
+
+	return relPath != ".." && !strings.HasPrefix(relPath, "../"), nil
+}
+
+// sourceCommit is a commit of the log of a merged source, and its member source.
+type sourceCommit struct {
+	src    *gitSource
+	commit *object.Commit
+}
+
+// sourceLog iterates the commits of the source as gitLog, calling fn with each commit and
+// its source. The logs of the members of a merged source are merged, newest first by
+// committer date; max_count applies to the merged log.
+func sourceLog(src *gitSource, exclude map[plumbing.Hash]bool, processor cmn.GitProcessor, fn func(*gitSource, *object.Commit) error) error {
+	funcName := "processors.sourceLog"
+	cmn.Debug("%s: begin", funcName)
+
+	if len(src.members) == 0 {
+		return gitLog(src, src.ref.Hash(), exclude, processor, func(commit *object.Commit) error {
+			return fn(src, commit)
+		})
+	}
+
+	// Collect the logs of the members.
+	member := processor
+	member.MaxCount = 0
+	var commits []sourceCommit
+	for _, m := range src.members {
+		err := gitLog(m, m.ref.Hash(), exclude, member, func(commit *object.Commit) error {
+			commits = append(commits, sourceCommit{src: m, commit: commit})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].commit.Committer.When.After(commits[j].commit.Committer.When)
+	})
+
+	// Iterate through the merged log.
+	for i := range commits {
+		if processor.MaxCount > 0 && i >= processor.MaxCount {
+			cmn.Debug("%s: reached max count: %d", funcName, processor.MaxCount)
+			break
+		}
+		err := fn(commits[i].src, commits[i].commit)
+		if err == storer.ErrStop {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	cmn.Debug("%s: %d commits of %d repos", funcName, len(commits), len(src.members))
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
+
+// sourceHead returns the commit of the ref of the source; the newest by committer date
+// of the members of a merged source.
+func sourceHead(src *gitSource) (*gitSource, *object.Commit, error) {
+	var head *object.Commit
+	var headSrc *gitSource
+	for _, m := range src.sources() {
+		commit, err := m.repo.CommitObject(m.ref.Hash())
+		if err != nil {
+			return nil, nil, err
+		}
+		if head == nil || commit.Committer.When.After(head.Committer.When) {
+			head, headSrc = commit, m
+		}
+	}
+
+	return headSrc, head, nil
+}
@@ -0,0 +1,104 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// otherGroup is the group of commits without a Conventional Commits type.
+const otherGroup = "other"
+
+var (
+	// conventionalHeader matches a Conventional Commits header; `type(scope)!: subject`.
+	conventionalHeader = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?(!)?: +(.+)$`)
+
+	// trailerLine matches a trailer or footer line; `Key: value`, `Key #value` or `BREAKING CHANGE: value`.
+	trailerLine = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z0-9][A-Za-z0-9-]*)(?:: +| +(#.*))(.*)$`)
+)
+
+// trailerKey normalizes the case of a trailer key; e.g. `Signed-Off-By` to `Signed-off-by`.
+func trailerKey(key string) string {
+	key = strings.ToLower(key)
+	if key == "breaking change" || key == "breaking-change" {
+		return "BREAKING CHANGE"
+	}
+
+	return strings.ToUpper(key[:1]) + key[1:]
+}
+
+// parseTrailers parses the paragraph as a block of trailers, reporting false if any line
+// is not a trailer or a continuation of one.
+func parseTrailers(paragraph string) (map[string][]string, bool) {
+	trailers := map[string][]string{}
+	lastKey := ""
+	for _, line := range strings.Split(paragraph, "\n") {
+		// Continuation of the previous trailer value.
+		if lastKey != "" && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			values := trailers[lastKey]
+			values[len(values)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+
+		match := trailerLine.FindStringSubmatch(line)
+		if match == nil {
+			return nil, false
+		}
+		lastKey = trailerKey(match[1])
+		trailers[lastKey] = append(trailers[lastKey], strings.TrimSpace(match[2]+match[3]))
+	}
+
+	return trailers, true
+}
+
+// parseMessage parses the commit message into its Conventional Commits header, body and
+// trailers.
+func parseMessage(message string) cmn.GitMessage {
+	parsed := cmn.GitMessage{Trailers: map[string][]string{}}
+
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	subject, body, _ := strings.Cut(message, "\n")
+	parsed.Subject = strings.TrimSpace(subject)
+
+	// The header of the subject line.
+	if match := conventionalHeader.FindStringSubmatch(parsed.Subject); match != nil {
+		parsed.Type = strings.ToLower(match[1])
+		parsed.Scope = strings.TrimSpace(match[2])
+		parsed.Breaking = match[3] == "!"
+		parsed.Subject = strings.TrimSpace(match[4])
+	}
+
+	// The trailers in the last paragraph of the body.
+	body = strings.TrimSpace(body)
+	paragraphs := strings.Split(body, "\n\n")
+	if last := paragraphs[len(paragraphs)-1]; last != "" {
+		if trailers, ok := parseTrailers(last); ok {
+			parsed.Trailers = trailers
+			body = strings.TrimSpace(strings.Join(paragraphs[:len(paragraphs)-1], "\n\n"))
+		}
+	}
+	parsed.Body = body
+
+	if _, ok := parsed.Trailers["BREAKING CHANGE"]; ok {
+		parsed.Breaking = true
+	}
+
+	return parsed
+}
+
+// groupByType groups the log entries by their Conventional Commits type, keeping the order
+// of the log; entries without a type are grouped as `other`.
+func groupByType(entries []cmn.GitLogEntry) map[string][]cmn.GitLogEntry {
+	groups := map[string][]cmn.GitLogEntry{}
+	for i := range entries {
+		group := entries[i].Parsed.Type
+		if group == "" {
+			group = otherGroup
+		}
+		groups[group] = append(groups[group], entries[i])
+	}
+
+	return groups
+}
@@ -0,0 +1,499 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"github.com/d5/tengo/v2"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// boolObject converts a bool into a Tengo bool.
+func boolObject(value bool) tengo.Object {
+	if value {
+		return tengo.TrueValue
+	}
+
+	return tengo.FalseValue
+}
+
+// gitRefObject converts a ref into a Tengo map.
+func gitRefObject(ref cmn.GitRef) *tengo.Map {
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"name":     &tengo.String{Value: ref.Name},
+		"short":    &tengo.String{Value: ref.Short},
+		"type":     &tengo.String{Value: ref.Type},
+		"hash":     &tengo.String{Value: ref.Hash},
+		"repo":     &tengo.String{Value: ref.Repo},
+		"shallow":  boolObject(ref.Shallow),
+		"boundary": stringsObject(ref.Boundary),
+		"worktree": &tengo.Map{Value: map[string]tengo.Object{
+			"dirty":     boolObject(ref.Worktree.Dirty),
+			"modified":  stringsObject(ref.Worktree.Modified),
+			"staged":    stringsObject(ref.Worktree.Staged),
+			"untracked": stringsObject(ref.Worktree.Untracked),
+		}},
+	}}
+}
+
+// signatureObject converts a git signature into a Tengo map.
+func signatureObject(sig object.Signature) *tengo.Map {
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"name":  &tengo.String{Value: sig.Name},
+		"email": &tengo.String{Value: sig.Email},
+		"when":  &tengo.Time{Value: sig.When},
+	}}
+}
+
+// gitSignatureObject converts the signature verification of a commit or tag into a Tengo map.
+func gitSignatureObject(sig cmn.GitSignature) *tengo.Map {
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"signed": boolObject(sig.Signed),
+		"type":   &tengo.String{Value: sig.Type},
+		"valid":  boolObject(sig.Valid),
+		"signer": &tengo.String{Value: sig.Signer},
+		"key_id": &tengo.String{Value: sig.KeyID},
+		"error":  &tengo.String{Value: sig.Error},
+	}}
+}
+
+// statsObject converts the file stats of a commit into a Tengo array of maps.
+func statsObject(stats object.FileStats) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(stats))}
+	for i := range stats {
+		arr.Value = append(arr.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"name":     &tengo.String{Value: stats[i].Name},
+			"addition": &tengo.Int{Value: int64(stats[i].Addition)},
+			"deletion": &tengo.Int{Value: int64(stats[i].Deletion)},
+		}})
+	}
+
+	return arr
+}
+
+// stringsObject converts a list of strings into a Tengo array.
+func stringsObject(values []string) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(values))}
+	for i := range values {
+		arr.Value = append(arr.Value, &tengo.String{Value: values[i]})
+	}
+
+	return arr
+}
+
+// gitMessageObject converts a parsed commit message into a Tengo map.
+func gitMessageObject(parsed cmn.GitMessage) *tengo.Map {
+	trailers := &tengo.Map{Value: make(map[string]tengo.Object, len(parsed.Trailers))}
+	for key, values := range parsed.Trailers {
+		trailers.Value[key] = stringsObject(values)
+	}
+
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"type":     &tengo.String{Value: parsed.Type},
+		"scope":    &tengo.String{Value: parsed.Scope},
+		"breaking": boolObject(parsed.Breaking),
+		"subject":  &tengo.String{Value: parsed.Subject},
+		"body":     &tengo.String{Value: parsed.Body},
+		"trailers": trailers,
+	}}
+}
+
+// gitDiffObject converts the file diffs of a commit into a Tengo array of maps.
+func gitDiffObject(fileDiffs []cmn.GitFileDiff) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(fileDiffs))}
+	for i := range fileDiffs {
+		hunks := &tengo.Array{Value: make([]tengo.Object, 0, len(fileDiffs[i].Hunks))}
+		for _, hunk := range fileDiffs[i].Hunks {
+			lines := &tengo.Array{Value: make([]tengo.Object, 0, len(hunk.Lines))}
+			for _, line := range hunk.Lines {
+				lines.Value = append(lines.Value, &tengo.Map{Value: map[string]tengo.Object{
+					"type":       &tengo.String{Value: line.Type},
+					"text":       &tengo.String{Value: line.Text},
+					"old_number": &tengo.Int{Value: int64(line.OldNumber)},
+					"new_number": &tengo.Int{Value: int64(line.NewNumber)},
+				}})
+			}
+			hunks.Value = append(hunks.Value, &tengo.Map{Value: map[string]tengo.Object{
+				"old_start": &tengo.Int{Value: int64(hunk.OldStart)},
+				"old_lines": &tengo.Int{Value: int64(hunk.OldLines)},
+				"new_start": &tengo.Int{Value: int64(hunk.NewStart)},
+				"new_lines": &tengo.Int{Value: int64(hunk.NewLines)},
+				"lines":     lines,
+			}})
+		}
+		arr.Value = append(arr.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"type":     &tengo.String{Value: fileDiffs[i].Type},
+			"old_path": &tengo.String{Value: fileDiffs[i].OldPath},
+			"new_path": &tengo.String{Value: fileDiffs[i].NewPath},
+			"binary":   boolObject(fileDiffs[i].Binary),
+			"hunks":    hunks,
+			"patch":    &tengo.String{Value: fileDiffs[i].Patch},
+		}})
+	}
+
+	return arr
+}
+
+// gitReferencesObject converts the issue and ticket references of a commit into a Tengo
+// array of maps.
+func gitReferencesObject(refs []cmn.GitReference) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(refs))}
+	for i := range refs {
+		arr.Value = append(arr.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"name":   &tengo.String{Value: refs[i].Name},
+			"text":   &tengo.String{Value: refs[i].Text},
+			"key":    &tengo.String{Value: refs[i].Key},
+			"number": &tengo.String{Value: refs[i].Number},
+			"url":    &tengo.String{Value: refs[i].URL},
+		}})
+	}
+
+	return arr
+}
+
+// gitPageObject converts the page of an all mode log into a Tengo map.
+func gitPageObject(allGit cmn.GitAll) *tengo.Map {
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"number":        &tengo.Int{Value: int64(allGit.Page)},
+		"total_pages":   &tengo.Int{Value: int64(allGit.TotalPages)},
+		"prev":          &tengo.Int{Value: int64(allGit.PrevPage)},
+		"next":          &tengo.Int{Value: int64(allGit.NextPage)},
+		"total_commits": &tengo.Int{Value: int64(allGit.TotalCommits)},
+	}}
+}
+
+// gitLogEntryObject converts a git log entry into a Tengo map.
+func gitLogEntryObject(entry cmn.GitLogEntry) *tengo.Map {
+	parents := make([]string, 0, len(entry.Commit.ParentHashes))
+	for i := range entry.Commit.ParentHashes {
+		parents = append(parents, entry.Commit.ParentHashes[i].String())
+	}
+
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"hash":                &tengo.String{Value: entry.Commit.Hash.String()},
+		"author":              signatureObject(entry.Commit.Author),
+		"committer":           signatureObject(entry.Commit.Committer),
+		"canonical_author":    gitAuthorObject(entry.Author),
+		"canonical_committer": gitAuthorObject(entry.Committer),
+		"message":             &tengo.String{Value: entry.Commit.Message},
+		"tree_hash":           &tengo.String{Value: entry.Commit.TreeHash.String()},
+		"parents":             stringsObject(parents),
+		"pgp_signature":       &tengo.String{Value: entry.Commit.PGPSignature},
+		"signature":           gitSignatureObject(entry.Signature),
+		"working_copy":        boolObject(entry.WorkingCopy),
+		"repo":                &tengo.String{Value: entry.Ref.Repo},
+		"boundary":            boolObject(entry.Boundary),
+		"references":          gitReferencesObject(entry.References),
+		"stats":               statsObject(entry.Stats),
+		"parsed":              gitMessageObject(entry.Parsed),
+		"diff":                gitDiffObject(entry.Diff),
+	}}
+}
+
+// gitLogEntriesObject converts a list of git log entries into a Tengo array of maps.
+func gitLogEntriesObject(entries []cmn.GitLogEntry) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(entries))}
+	for i := range entries {
+		arr.Value = append(arr.Value, gitLogEntryObject(entries[i]))
+	}
+
+	return arr
+}
+
+// gitGroupsObject converts grouped git log entries into a Tengo map of arrays.
+func gitGroupsObject(groups map[string][]cmn.GitLogEntry) *tengo.Map {
+	m := &tengo.Map{Value: make(map[string]tengo.Object, len(groups))}
+	for group, entries := range groups {
+		m.Value[group] = gitLogEntriesObject(entries)
+	}
+
+	return m
+}
+
+// gitAuthorObject converts a canonical identity into a Tengo map.
+func gitAuthorObject(author cmn.GitAuthor) *tengo.Map {
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"name":  &tengo.String{Value: author.Name},
+		"email": &tengo.String{Value: author.Email},
+	}}
+}
+
+// gitAuthorsObject converts a list of distinct authors into a Tengo array of maps.
+func gitAuthorsObject(authors []cmn.GitAuthor) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(authors))}
+	for i := range authors {
+		arr.Value = append(arr.Value, gitAuthorObject(authors[i]))
+	}
+
+	return arr
+}
+
+// gitFileObject converts the git log of a file into a Tengo map.
+func gitFileObject(gitFile cmn.GitFile) *tengo.Map {
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"path":       &tengo.String{Value: gitFile.Path},
+		"commits":    gitLogEntriesObject(gitFile.Commits),
+		"first_date": &tengo.Time{Value: gitFile.FirstDate},
+		"last_date":  &tengo.Time{Value: gitFile.LastDate},
+		"authors":    gitAuthorsObject(gitFile.Authors),
+		"count":      &tengo.Int{Value: int64(gitFile.Count)},
+		"old_paths":  stringsObject(gitFile.OldPaths),
+	}}
+}
+
+// gitTagObject converts a tag and its git log into a Tengo map.
+func gitTagObject(gitTag cmn.GitTag) *tengo.Map {
+	var tagger tengo.Object = tengo.UndefinedValue
+	if gitTag.Annotated {
+		tagger = signatureObject(gitTag.Tagger)
+	}
+
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"name":      &tengo.String{Value: gitTag.Name},
+		"version":   &tengo.String{Value: gitTag.Version},
+		"annotated": boolObject(gitTag.Annotated),
+		"tagger":    tagger,
+		"signature": gitSignatureObject(gitTag.Signature),
+		"message":   &tengo.String{Value: gitTag.Message},
+		"target":    gitLogEntryObject(gitTag.Target),
+		"commits":   gitLogEntriesObject(gitTag.Commits),
+		"groups":    gitGroupsObject(gitTag.Groups),
+		"previous":  &tengo.String{Value: gitTag.Previous},
+	}}
+}
+
+// gitBlameLineObject converts the last change of a line into a Tengo map.
+func gitBlameLineObject(line cmn.GitBlameLine) *tengo.Map {
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"number": &tengo.Int{Value: int64(line.Number)},
+		"text":   &tengo.String{Value: line.Text},
+		"name":   &tengo.String{Value: line.Name},
+		"email":  &tengo.String{Value: line.Email},
+		"date":   &tengo.Time{Value: line.Date},
+		"hash":   &tengo.String{Value: line.Hash},
+	}}
+}
+
+// gitBlameObject converts the line-level authorship of a file into a Tengo map.
+func gitBlameObject(gitBlame cmn.GitBlame) *tengo.Map {
+	authors := &tengo.Array{Value: make([]tengo.Object, 0, len(gitBlame.Authors))}
+	for i := range gitBlame.Authors {
+		authors.Value = append(authors.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"name":  &tengo.String{Value: gitBlame.Authors[i].Name},
+			"email": &tengo.String{Value: gitBlame.Authors[i].Email},
+			"lines": &tengo.Int{Value: int64(gitBlame.Authors[i].Lines)},
+			"share": &tengo.Float{Value: gitBlame.Authors[i].Share},
+		}})
+	}
+
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"path":        &tengo.String{Value: gitBlame.Path},
+		"lines":       &tengo.Int{Value: int64(gitBlame.Lines)},
+		"authors":     authors,
+		"last_change": gitBlameLineObject(gitBlame.LastChange),
+	}}
+}
+
+// gitContributorsObject converts the aggregated statistics of all authors into a Tengo array of maps.
+func gitContributorsObject(contributors cmn.GitContributors) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(contributors.Authors))}
+	for _, author := range contributors.Authors {
+		arr.Value = append(arr.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"name":       &tengo.String{Value: author.Name},
+			"email":      &tengo.String{Value: author.Email},
+			"commits":    &tengo.Int{Value: int64(author.Commits)},
+			"first_date": &tengo.Time{Value: author.FirstDate},
+			"last_date":  &tengo.Time{Value: author.LastDate},
+			"additions":  &tengo.Int{Value: int64(author.Additions)},
+			"deletions":  &tengo.Int{Value: int64(author.Deletions)},
+			"files":      stringsObject(author.Files),
+		}})
+	}
+
+	return arr
+}
+
+// gitGraphObject converts the commit graph into a Tengo map.
+func gitGraphObject(graph cmn.GitGraph) *tengo.Map {
+	nodes := &tengo.Array{Value: make([]tengo.Object, 0, len(graph.Nodes))}
+	for _, node := range graph.Nodes {
+		nodes.Value = append(nodes.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"hash":     &tengo.String{Value: node.Hash},
+			"short":    &tengo.String{Value: node.Short},
+			"lane":     &tengo.Int{Value: int64(node.Lane)},
+			"parents":  stringsObject(node.Parents),
+			"subject":  &tengo.String{Value: node.Subject},
+			"author":   gitAuthorObject(node.Author),
+			"date":     &tengo.Time{Value: node.Date},
+			"branches": stringsObject(node.Branches),
+			"tags":     stringsObject(node.Tags),
+			"merge":    boolObject(node.Merge),
+		}})
+	}
+	edges := &tengo.Array{Value: make([]tengo.Object, 0, len(graph.Edges))}
+	for _, edge := range graph.Edges {
+		edges.Value = append(edges.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"from":  &tengo.String{Value: edge.From},
+			"to":    &tengo.String{Value: edge.To},
+			"merge": boolObject(edge.Merge),
+		}})
+	}
+	lanes := &tengo.Array{Value: make([]tengo.Object, 0, len(graph.Lanes))}
+	for _, lane := range graph.Lanes {
+		lanes.Value = append(lanes.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"index": &tengo.Int{Value: int64(lane.Index)},
+			"name":  &tengo.String{Value: lane.Name},
+			"head":  &tengo.String{Value: lane.Head},
+			"fork":  &tengo.String{Value: lane.Fork},
+		}})
+	}
+
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"nodes":   nodes,
+		"edges":   edges,
+		"lanes":   lanes,
+		"merges":  stringsObject(graph.Merges),
+		"mermaid": &tengo.String{Value: graph.Mermaid},
+	}}
+}
+
+// gitTreeObject converts the files of a tree into a Tengo array of maps.
+func gitTreeObject(entries []cmn.GitTreeEntry) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(entries))}
+	for _, entry := range entries {
+		arr.Value = append(arr.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"path": &tengo.String{Value: entry.Path},
+			"name": &tengo.String{Value: entry.Name},
+			"size": &tengo.Int{Value: entry.Size},
+			"hash": &tengo.String{Value: entry.Hash},
+			"lfs":  gitLFSObject(entry.LFS),
+		}})
+	}
+
+	return arr
+}
+
+// gitLFSObject converts a Git LFS pointer into a Tengo map; undefined if nil.
+func gitLFSObject(pointer *cmn.GitLFSPointer) tengo.Object {
+	if pointer == nil {
+		return tengo.UndefinedValue
+	}
+
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"oid":  &tengo.String{Value: pointer.OID},
+		"size": &tengo.Int{Value: pointer.Size},
+	}}
+}
+
+// gitActivityObject converts the activity of the git log into a Tengo map.
+func gitActivityObject(activity cmn.GitActivity) *tengo.Map {
+	buckets := &tengo.Array{Value: make([]tengo.Object, 0, len(activity.Buckets))}
+	for _, bucket := range activity.Buckets {
+		buckets.Value = append(buckets.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"key":       &tengo.String{Value: bucket.Key},
+			"start":     &tengo.Time{Value: bucket.Start},
+			"group":     &tengo.String{Value: bucket.Group},
+			"commits":   &tengo.Int{Value: int64(bucket.Commits)},
+			"additions": &tengo.Int{Value: int64(bucket.Additions)},
+			"deletions": &tengo.Int{Value: int64(bucket.Deletions)},
+			"authors":   gitAuthorsObject(bucket.Authors),
+		}})
+	}
+
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"period":   &tengo.String{Value: activity.Period},
+		"group_by": &tengo.String{Value: activity.GroupBy},
+		"groups":   stringsObject(activity.Groups),
+		"buckets":  buckets,
+		"commits":  &tengo.Int{Value: int64(activity.Commits)},
+	}}
+}
+
+// gitInventoryGroupsObject converts the groups of an inventory into a Tengo array of maps.
+func gitInventoryGroupsObject(groups []cmn.GitInventoryGroup) *tengo.Array {
+	arr := &tengo.Array{Value: make([]tengo.Object, 0, len(groups))}
+	for _, group := range groups {
+		arr.Value = append(arr.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"name":   &tengo.String{Value: group.Name},
+			"files":  &tengo.Int{Value: int64(group.Files)},
+			"lines":  &tengo.Int{Value: int64(group.Lines)},
+			"size":   &tengo.Int{Value: group.Size},
+			"binary": &tengo.Int{Value: int64(group.Binary)},
+		}})
+	}
+
+	return arr
+}
+
+// gitInventoryObject converts an inventory into a Tengo map.
+func gitInventoryObject(inventory cmn.GitInventory) *tengo.Map {
+	largest := &tengo.Array{Value: make([]tengo.Object, 0, len(inventory.Largest))}
+	for _, file := range inventory.Largest {
+		largest.Value = append(largest.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"path":   &tengo.String{Value: file.Path},
+			"group":  &tengo.String{Value: file.Group},
+			"size":   &tengo.Int{Value: file.Size},
+			"lines":  &tengo.Int{Value: int64(file.Lines)},
+			"binary": boolObject(file.Binary),
+			"lfs":    boolObject(file.LFS),
+		}})
+	}
+
+	obj := &tengo.Map{Value: map[string]tengo.Object{
+		"group_by": &tengo.String{Value: inventory.GroupBy},
+		"hash":     &tengo.String{Value: inventory.Hash},
+		"files":    &tengo.Int{Value: int64(inventory.Files)},
+		"lines":    &tengo.Int{Value: int64(inventory.Lines)},
+		"size":     &tengo.Int{Value: inventory.Size},
+		"text":     &tengo.Int{Value: int64(inventory.Text)},
+		"binary":   &tengo.Int{Value: int64(inventory.Binary)},
+		"groups":   gitInventoryGroupsObject(inventory.Groups),
+		"largest":  largest,
+	}}
+	if inventory.Base != nil {
+		obj.Value["base"] = gitInventoryObject(*inventory.Base)
+	}
+	if inventory.Delta != nil {
+		obj.Value["delta"] = &tengo.Map{Value: map[string]tengo.Object{
+			"files":  &tengo.Int{Value: int64(inventory.Delta.Files)},
+			"lines":  &tengo.Int{Value: int64(inventory.Delta.Lines)},
+			"size":   &tengo.Int{Value: inventory.Delta.Size},
+			"text":   &tengo.Int{Value: int64(inventory.Delta.Text)},
+			"binary": &tengo.Int{Value: int64(inventory.Delta.Binary)},
+			"groups": gitInventoryGroupsObject(inventory.Delta.Groups),
+		}}
+	}
+
+	return obj
+}
+
+// gitOwnersObject converts the owners of the files into a Tengo map.
+func gitOwnersObject(owners cmn.GitOwners) *tengo.Map {
+	files := &tengo.Array{Value: make([]tengo.Object, 0, len(owners.Files))}
+	byPath := make(map[string]tengo.Object, len(owners.Owners))
+	for _, entry := range owners.Files {
+		sections := &tengo.Array{Value: make([]tengo.Object, 0, len(entry.Sections))}
+		for _, section := range entry.Sections {
+			sections.Value = append(sections.Value, &tengo.Map{Value: map[string]tengo.Object{
+				"name":      &tengo.String{Value: section.Name},
+				"optional":  boolObject(section.Optional),
+				"approvals": &tengo.Int{Value: int64(section.Approvals)},
+				"pattern":   &tengo.String{Value: section.Pattern},
+				"line":      &tengo.Int{Value: int64(section.Line)},
+				"owners":    stringsObject(section.Owners),
+			}})
+		}
+		files.Value = append(files.Value, &tengo.Map{Value: map[string]tengo.Object{
+			"path":     &tengo.String{Value: entry.Path},
+			"owners":   stringsObject(entry.Owners),
+			"sections": sections,
+		}})
+		byPath[entry.Path] = stringsObject(entry.Owners)
+	}
+
+	return &tengo.Map{Value: map[string]tengo.Object{
+		"file":    &tengo.String{Value: owners.File},
+		"files":   files,
+		"owners":  &tengo.Map{Value: byPath},
+		"unowned": stringsObject(owners.Unowned),
+	}}
+}
@@ -13,9 +13,9 @@ import (
 	"github.com/Masterminds/sprig/v3"
 	"github.com/d5/tengo/v2"
 	"github.com/d5/tengo/v2/stdlib"
-	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/jason-dour/hugo-preproc/internal/cmn"
 )
 
@@ -55,29 +55,44 @@ func cmdEach(command string, files []string) error {
 	return nil
 }
 
-// makeScript takes the provided script definition and returns a script instance.
-func makeScript(script string) *tengo.Compiled {
+// makeScript takes the provided script definition and returns a compiled script
+// instance, with the named variables declared for the caller to set. The given builtin
+// modules are importable next to the Tengo standard library.
+func makeScript(script string, modules map[string]map[string]tengo.Object, vars ...string) (*tengo.Compiled, error) {
 	funcName := "processors.makeScript"
 	cmn.Debug("%s: begin", funcName)
 
 	var scr *tengo.Script
 	if strings.HasPrefix(script, "file://") {
-		cmn.Debug("%s: reading script from file: %s", funcName, script[6:])
+		cmn.Debug("%s: reading script from file: %s", funcName, script[7:])
+		src, err := os.ReadFile(script[7:])
+		if err != nil {
+			return nil, err
+		}
+		scr = tengo.NewScript(src)
 	} else {
 		scr = tengo.NewScript([]byte(script))
 	}
 
-	scr.SetImports(stdlib.GetModuleMap(stdlib.AllModuleNames()...))
-	scr.Add("files", nil)
-	scr.Add("file", nil)
+	imports := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	for name, attrs := range modules {
+		imports.AddBuiltinModule(name, attrs)
+	}
+	scr.SetImports(imports)
+	for i := range vars {
+		err := scr.Add(vars[i], nil)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	compiled, err := scr.Compile()
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	cmn.Debug("%s: end", funcName)
-	return compiled
+	return compiled, nil
 }
 
 // scriptEach runs the script once for each file.
@@ -85,7 +100,10 @@ func scriptEach(script string, files []string) error {
 	funcName := "processors.scriptEach"
 	cmn.Debug("%s: begin", funcName)
 
-	scr := makeScript(script)
+	scr, err := makeScript(script, nil, "file")
+	if err != nil {
+		return err
+	}
 
 	for i := range files {
 		cmn.Debug("%s: setting file: %v", funcName, files[i])
@@ -109,9 +127,12 @@ func scriptAll(script string, files []string) error {
 	funcName := "processors.scriptAll"
 	cmn.Debug("%s: begin", funcName)
 
-	scr := makeScript(script)
+	scr, err := makeScript(script, nil, "files")
+	if err != nil {
+		return err
+	}
 	cmn.Debug("%s: setting files: %v", funcName, files)
-	err := scr.Set("files", &StringArray{Value: files})
+	err = scr.Set("files", &StringArray{Value: files})
 	if err != nil {
 		return err
 	}
@@ -187,69 +208,42 @@ func Execs(configs *cmn.Configs) error {
 	return nil
 }
 
-// gitHead - Process Head mode git log processor.
-func gitHead(repo *git.Repository, ref *plumbing.Reference, processor cmn.GitProcessor) error {
-	funcName := "processors.gitHead"
-	cmn.Debug("%s: begin", funcName)
-
-	// Grab the HEAD commit.
-	commit, err := repo.CommitObject(ref.Hash())
+// executeTemplate processes the text as a template against the provided data, with the
+// sprig functions and the given additional functions.
+func executeTemplate(name string, text string, funcs template.FuncMap, data any) (string, error) {
+	tmpl, err := template.New(name).Funcs(sprig.FuncMap()).Funcs(funcs).Parse(text)
 	if err != nil {
-		return err
+		return "", err
 	}
-	cmn.Debug("%s: head commit: %v", funcName, commit.Hash.String())
 
-	// Grab the HEAD commit stats.
-	commitStats, err := commit.Stats()
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, data)
 	if err != nil {
-		return err
+		return "", err
 	}
-	cmn.Debug("%s: head commit stats length: %d", funcName, len(commitStats))
 
-	// Process the file in the config as a template to create the file name.
-	fileTemplate, err := template.New("fileTemplate").Funcs(sprig.FuncMap()).Parse(processor.File)
-	if err != nil {
-		return err
-	}
-	var templateFile bytes.Buffer
-	err = fileTemplate.Execute(&templateFile, cmn.GitLogEntry{
-		Commit: commit,
-		Stats:  commitStats,
-	})
-	if err != nil {
-		return err
-	}
-	cmn.Debug("%s: file: %s", funcName, templateFile.String())
+	return out.String(), nil
+}
 
-	// Process the output template in the config.
-	outTemplate, err := template.New("outTemplate").Funcs(sprig.FuncMap()).Parse(processor.Template)
-	if err != nil {
-		return err
-	}
-	var templateOut bytes.Buffer
-	err = outTemplate.Execute(&templateOut, cmn.GitLogEntry{
-		Commit: commit,
-		Stats:  commitStats,
-	})
-	if err != nil {
-		return err
-	}
-	cmn.Debug("%s: templateOut length: %d", funcName, len(templateOut.String()))
+// writeFile creates the named file, and its parent directories, and writes the content.
+func writeFile(name string, content string) error {
+	funcName := "processors.writeFile"
+	cmn.Debug("%s: begin", funcName)
 
 	// Create the file.
-	err = os.MkdirAll(filepath.Dir(templateFile.String()), 0755)
+	err := os.MkdirAll(filepath.Dir(name), 0755)
 	if err != nil {
 		return err
 	}
-	outFile, err := os.Create(templateFile.String())
+	outFile, err := os.Create(name)
 	if err != nil {
 		return err
 	}
 	defer outFile.Close()
-	cmn.Debug("%s: created file: %s", funcName, templateFile.String())
+	cmn.Debug("%s: created file: %s", funcName, name)
 
 	// Write the output to the file.
-	bytesWritten, err := outFile.WriteString(templateOut.String())
+	bytesWritten, err := outFile.WriteString(content)
 	if err != nil {
 		return err
 	}
@@ -259,80 +253,73 @@ func gitHead(repo *git.Repository, ref *plumbing.Reference, processor cmn.GitPro
 	return nil
 }
 
-// gitEach - Process Each mode git log processor.
-func gitEach(repo *git.Repository, ref *plumbing.Reference, processor cmn.GitProcessor) error {
-	funcName := "processors.gitEach"
+// gitScript compiles the script of the git processor, if one is defined, declaring
+// the `file` and `ref` variables and the named data variables; the `git` module reads
+// from the repository of the source.
+func gitScript(src *gitSource, processor cmn.GitProcessor, vars ...string) (*tengo.Compiled, error) {
+	if len(processor.Script) == 0 {
+		return nil, nil
+	}
+
+	modules := map[string]map[string]tengo.Object{"git": gitModule(src)}
+	return makeScript(processor.Script, modules, append([]string{"file", "ref"}, vars...)...)
+}
+
+// gitOutput processes the git data through the processor. With a compiled script, the
+// rendered file name, the ref of the source and the provided variables are set and the
+// script is run; otherwise the template is rendered and written to the file.
+func gitOutput(src *gitSource, processor cmn.GitProcessor, scr *tengo.Compiled, data any, vars map[string]tengo.Object) error {
+	funcName := "processors.gitOutput"
 	cmn.Debug("%s: begin", funcName)
 
-	// Get the commit history in an interator.
-	commitIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	// Process the file in the config as a template to create the file name.
+	file, err := executeTemplate("fileTemplate", processor.File, gitFuncMap(src), data)
 	if err != nil {
 		return err
 	}
-	cmn.Debug("%s: created git history iterator", funcName)
+	cmn.Debug("%s: file: %s", funcName, file)
 
-	// Iterate through the commits.
-	err = commitIter.ForEach(func(commit *object.Commit) error {
-		cmn.Debug("%s: commit %s", funcName, commit.Hash.String()[0:7])
-		// Grab the commit stats.
-		commitStats, err := commit.Stats()
-		if err != nil {
-			return err
+	// Run the script, if defined.
+	if scr != nil {
+		// Create the parent directories so the script can simply create the file.
+		if len(file) > 0 {
+			err = os.MkdirAll(filepath.Dir(file), 0755)
+			if err != nil {
+				return err
+			}
 		}
-		defer commitIter.Close()
-		cmn.Debug("%s: commit %s: stats length: %d", funcName, commit.Hash.String()[0:7], len(commitStats))
-
-		// Process the file in the config as a template to create the file name.
-		fileTemplate, err := template.New("fileTemplate").Funcs(sprig.FuncMap()).Parse(processor.File)
+		err = scr.Set("file", file)
 		if err != nil {
 			return err
 		}
-		var templateFile bytes.Buffer
-		err = fileTemplate.Execute(&templateFile, cmn.GitLogEntry{
-			Commit: commit,
-			Stats:  commitStats,
-		})
+		err = scr.Set("ref", gitRefObject(src.info))
 		if err != nil {
 			return err
 		}
-		cmn.Debug("%s: commit %s: file: %s", funcName, commit.Hash.String()[0:7], templateFile.String())
-
-		// Process the output template in the config.
-		outTemplate, err := template.New("outTemplate").Funcs(sprig.FuncMap()).Parse(processor.Template)
-		if err != nil {
-			return err
+		for name, value := range vars {
+			err = scr.Set(name, value)
+			if err != nil {
+				return err
+			}
 		}
-		var templateOut bytes.Buffer
-		err = outTemplate.Execute(&templateOut, cmn.GitLogEntry{
-			Commit: commit,
-			Stats:  commitStats,
-		})
+		cmn.Debug("%s: run script", funcName)
+		err = scr.Run()
 		if err != nil {
 			return err
 		}
-		cmn.Debug("%s: commit %s: templateOut length: %d", funcName, commit.Hash.String()[0:7], len(templateOut.String()))
 
-		// Create the file.
-		err = os.MkdirAll(filepath.Dir(templateFile.String()), 0755)
-		if err != nil {
-			return err
-		}
-		outFile, err := os.Create(templateFile.String())
-		if err != nil {
-			return err
-		}
-		defer outFile.Close()
-		cmn.Debug("%s: commit %s: created file: %s", funcName, commit.Hash.String()[0:7], templateFile.String())
+		cmn.Debug("%s: end", funcName)
+		return nil
+	}
 
-		// Write the output to the file.
-		bytesWritten, err := outFile.WriteString(templateOut.String())
-		if err != nil {
-			return err
-		}
-		cmn.Debug("%s: commit %s: wrote %d bytes to file", funcName, commit.Hash.String()[0:7], bytesWritten)
+	// Process the output template in the config.
+	out, err := executeTemplate("outTemplate", processor.Template, gitFuncMap(src), data)
+	if err != nil {
+		return err
+	}
+	cmn.Debug("%s: templateOut length: %d", funcName, len(out))
 
-		return nil
-	})
+	err = writeFile(file, out)
 	if err != nil {
 		return err
 	}
@@ -341,94 +328,219 @@ func gitEach(repo *git.Repository, ref *plumbing.Reference, processor cmn.GitPro
 	return nil
 }
 
-// gitAll - Process All mode git log processor.
-func gitAll(repo *git.Repository, ref *plumbing.Reference, processor cmn.GitProcessor) error {
-	funcName := "processors.gitAll"
+// newGitLogEntry builds the git log entry for the commit of the source, with the commit
+// data enabled by the processor.
+func newGitLogEntry(src *gitSource, processor cmn.GitProcessor, commit *object.Commit) (cmn.GitLogEntry, error) {
+	funcName := "processors.newGitLogEntry"
 	cmn.Debug("%s: begin", funcName)
 
-	// Get the commit history in an interator.
-	commitIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	// Grab the commit stats.
+	stats, err := commitStats(src, commit)
+	if err != nil {
+		return cmn.GitLogEntry{}, err
+	}
+	cmn.Debug("%s: commit %s: stats length: %d", funcName, commit.Hash.String()[0:7], len(stats))
+
+	entry := cmn.GitLogEntry{
+		Commit:    commit,
+		Author:    src.mailmap.resolve(commit.Author.Name, commit.Author.Email),
+		Committer: src.mailmap.resolve(commit.Committer.Name, commit.Committer.Email),
+		Stats:     stats,
+		Parsed:    parseMessage(commit.Message),
+		Ref:       src.info,
+		Boundary:  src.shallow.isBoundary(commit.Hash),
+	}
+
+	// Find the issue and ticket references.
+	entry.References, err = parseReferences(src.references, commit.Message)
+	if err != nil {
+		return cmn.GitLogEntry{}, err
+	}
+
+	// Verify the commit signature.
+	entry.Signature, err = verifySignature(src.keyring, commit.PGPSignature, commit.EncodeWithoutSignature)
+	if err != nil {
+		return cmn.GitLogEntry{}, err
+	}
+
+	// Grab the commit diff, if enabled; a boundary commit has no parent to compare to.
+	if processor.Diff && !entry.Boundary {
+		entry.Diff, err = commitDiff(commit)
+		if err != nil {
+			return cmn.GitLogEntry{}, err
+		}
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return entry, nil
+}
+
+// gitHead - Process Head mode git log processor.
+func gitHead(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitHead"
+	cmn.Debug("%s: begin", funcName)
+
+	// Grab the newest commit passing the filters; the HEAD commit when unfiltered.
+	var commit *object.Commit
+	err := sourceLog(src, nil, processor, func(member *gitSource, c *object.Commit) error {
+		// The commit is processed with its source; a member of a merged source.
+		src, commit = member, c
+		return storer.ErrStop
+	})
 	if err != nil {
 		return err
 	}
-	defer commitIter.Close()
-	cmn.Debug("%s: created git history iterator", funcName)
+	if commit == nil {
+		cmn.Debug("%s: no commit matches filters, skipping", funcName)
+		return nil
+	}
+	cmn.Debug("%s: head commit: %v", funcName, commit.Hash.String())
 
-	var allGit cmn.GitAll
+	entry, err := newGitLogEntry(src, processor, commit)
+	if err != nil {
+		return err
+	}
 
-	// Grab the HEAD commit.
-	allGit.Head.Commit, err = repo.CommitObject(ref.Hash())
+	scr, err := gitScript(src, processor, "commit")
 	if err != nil {
 		return err
 	}
-	cmn.Debug("%s: head commit: %v", funcName, allGit.Head.Commit.Hash.String())
 
-	allGit.Head.Stats, err = allGit.Head.Commit.Stats()
+	err = gitOutput(src, processor, scr, entry, map[string]tengo.Object{
+		"commit": gitLogEntryObject(entry),
+	})
+	if err != nil {
+		return err
+	}
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
+
+// gitEach - Process Each mode git log processor; with a state file, only the commits
+// since the last run are processed.
+func gitEach(src *gitSource, handler cmn.Git, processor cmn.GitProcessor, key string) error {
+	funcName := "processors.gitEach"
+	cmn.Debug("%s: begin", funcName)
+
+	scr, err := gitScript(src, processor, "commit")
 	if err != nil {
 		return err
 	}
-	cmn.Debug("%s: head commit stats length: %d", funcName, len(allGit.Head.Stats))
+
+	// Grab the commits processed by the last run; by repository of a merged source.
+	var processed map[plumbing.Hash]bool
+	for _, member := range src.sources() {
+		commits, err := src.state.processed(member, memberKey(key, member), handler, processor)
+		if err != nil {
+			return err
+		}
+		for hash := range commits {
+			if processed == nil {
+				processed = map[plumbing.Hash]bool{}
+			}
+			processed[hash] = true
+		}
+	}
 
 	// Iterate through the commits.
-	// allGit.Commits = []GitLogEntry{}
-	err = commitIter.ForEach(func(commit *object.Commit) error {
-		commitStats, err := commit.Stats()
+	err = sourceLog(src, processed, processor, func(member *gitSource, commit *object.Commit) error {
+		cmn.Debug("%s: commit %s", funcName, commit.Hash.String()[0:7])
+		entry, err := newGitLogEntry(member, processor, commit)
 		if err != nil {
 			return err
 		}
-		cmn.Debug("%s: commit %s: stats length: %d", funcName, commit.Hash.String()[0:7], len(commitStats))
-		allGit.Commits = append(allGit.Commits, cmn.GitLogEntry{
-			Commit: commit,
-			Stats:  commitStats,
+
+		return gitOutput(member, processor, scr, entry, map[string]tengo.Object{
+			"commit": gitLogEntryObject(entry),
 		})
-		return nil
 	})
 	if err != nil {
 		return err
 	}
 
-	// Process the file in the config as a template to create the file name.
-	fileTemplate, err := template.New("fileTemplate").Funcs(sprig.FuncMap()).Parse(processor.File)
-	if err != nil {
-		return err
+	for _, member := range src.sources() {
+		err = src.state.record(member, memberKey(key, member), handler, processor)
+		if err != nil {
+			return err
+		}
 	}
-	var templateFile bytes.Buffer
-	err = fileTemplate.Execute(&templateFile, allGit)
+
+	cmn.Debug("%s: end", funcName)
+	return nil
+}
+
+// gitAll - Process All mode git log processor.
+func gitAll(src *gitSource, processor cmn.GitProcessor) error {
+	funcName := "processors.gitAll"
+	cmn.Debug("%s: begin", funcName)
+
+	// Grab the HEAD commit.
+	headSrc, headCommit, err := sourceHead(src)
 	if err != nil {
 		return err
 	}
-	cmn.Debug("%s: file: %s", funcName, templateFile.String())
+	cmn.Debug("%s: head commit: %v", funcName, headCommit.Hash.String())
 
-	// Process the output template in the config.
-	outTemplate, err := template.New("outTemplate").Funcs(sprig.FuncMap()).Parse(processor.Template)
+	head, err := newGitLogEntry(headSrc, processor, headCommit)
 	if err != nil {
 		return err
 	}
-	var templateOut bytes.Buffer
-	err = outTemplate.Execute(&templateOut, allGit)
+
+	// Iterate through the commits.
+	var commits []cmn.GitLogEntry
+	err = sourceLog(src, nil, processor, func(member *gitSource, commit *object.Commit) error {
+		entry, err := newGitLogEntry(member, processor, commit)
+		if err != nil {
+			return err
+		}
+		commits = append(commits, entry)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	cmn.Debug("%s: templateOut length: %d", funcName, len(templateOut.String()))
 
-	// Create the file.
-	err = os.MkdirAll(filepath.Dir(templateFile.String()), 0755)
+	scr, err := gitScript(src, processor, "commits", "head", "groups", "page")
 	if err != nil {
 		return err
 	}
-	outFile, err := os.Create(templateFile.String())
-	if err != nil {
-		return err
+
+	// Split the commits into pages; a single page without a page size.
+	pageSize := processor.PageSize
+	if pageSize == 0 {
+		pageSize = max(len(commits), 1)
 	}
-	defer outFile.Close()
-	cmn.Debug("%s: created file: %s", funcName, templateFile.String())
+	totalPages := max((len(commits)+pageSize-1)/pageSize, 1)
+	cmn.Debug("%s: %d commits; %d pages", funcName, len(commits), totalPages)
+
+	for page := 1; page <= totalPages; page++ {
+		allGit := cmn.GitAll{
+			Commits:      commits[min((page-1)*pageSize, len(commits)):min(page*pageSize, len(commits))],
+			Head:         head,
+			Ref:          src.info,
+			Page:         page,
+			TotalPages:   totalPages,
+			TotalCommits: len(commits),
+		}
+		if page > 1 {
+			allGit.PrevPage = page - 1
+		}
+		if page < totalPages {
+			allGit.NextPage = page + 1
+		}
+		allGit.Groups = groupByType(allGit.Commits)
 
-	// Write the output to the file.
-	bytesWritten, err := outFile.WriteString(templateOut.String())
-	if err != nil {
-		return err
+		err = gitOutput(src, processor, scr, allGit, map[string]tengo.Object{
+			"commits": gitLogEntriesObject(allGit.Commits),
+			"head":    gitLogEntryObject(allGit.Head),
+			"groups":  gitGroupsObject(allGit.Groups),
+			"page":    gitPageObject(allGit),
+		})
+		if err != nil {
+			return err
+		}
 	}
-	cmn.Debug("%s: wrote %d bytes to file", funcName, bytesWritten)
 
 	cmn.Debug("%s: end", funcName)
 	return nil
@@ -439,64 +551,121 @@ func Gits(configs *cmn.Configs) error {
 	funcName := "processors.Gits"
 	cmn.Debug("%s: begin", funcName)
 
+	// Load the state of incremental processing.
+	state, err := loadState(configs.State)
+	if err != nil {
+		return err
+	}
+
 	// Iterate through the configured git log handlers.
 	cmn.Debug("%s: iterating gits: %d", funcName, len(configs.Gits))
 	for i := range configs.Gits {
-		// Define the path to the git repository.
-		var repoPath string
-		if configs.Gits[i].Path != "" {
-			repoPath = configs.Gits[i].Path
-		} else {
-			repoPath = "."
-		}
-		cmn.Debug("%s: repo path: %s", funcName, repoPath)
-
-		// Open the repository.
-		repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{
-			DetectDotGit:          true,
-			EnableDotGitCommonDir: true,
-		})
-		if err != nil {
-			return err
-		}
-		cmn.Debug("%s: repo opened", funcName)
-
-		// Get the HEAD commit.
-		ref, err := repo.Head()
+		// Open the repositories and resolve the refs to process.
+		sources, err := handlerSources(state, configs.Gits[i])
 		if err != nil {
 			return err
 		}
-		cmn.Debug("%s: head commit: %v", funcName, ref.Hash().String())
-
-		// Iterate through the configured processors.
-		cmn.Debug("%s: git %d: iterating processors: %d", funcName, i, len(configs.Gits))
-		for j := range configs.Gits[i].Processors {
-			cmn.Debug("%s: git %d: processor %d", funcName, i, j)
-			// Get the mode.
-			switch strings.ToLower(configs.Gits[i].Processors[j].Mode) {
-			case "head":
-				// Process the HEAD git config.
-				cmn.Debug("%s: git %d: processor %d: mode: head", funcName, i, j)
-				err := gitHead(repo, ref, configs.Gits[i].Processors[j])
-				if err != nil {
-					return err
-				}
-			case "each":
-				// Process the Each git config.
-				cmn.Debug("%s: git %d: processor %d: mode: each", funcName, i, j)
-				err := gitEach(repo, ref, configs.Gits[i].Processors[j])
-				if err != nil {
-					return err
-				}
-			case "all":
-				// Process the All git config.
-				cmn.Debug("%s: git %d: processor %d: mode: all", funcName, i, j)
-				err := gitAll(repo, ref, configs.Gits[i].Processors[j])
-				if err != nil {
-					return err
+		cmn.Debug("%s: git %d: iterating refs: %d", funcName, i, len(sources))
+
+		for _, src := range sources {
+			cmn.Debug("%s: git %d: ref: %s: commit: %v", funcName, i, src.info.Name, src.info.Hash)
+
+			// Iterate through the configured processors.
+			cmn.Debug("%s: git %d: iterating processors: %d", funcName, i, len(configs.Gits[i].Processors))
+			for j := range configs.Gits[i].Processors {
+				cmn.Debug("%s: git %d: processor %d", funcName, i, j)
+				// Get the mode.
+				switch strings.ToLower(configs.Gits[i].Processors[j].Mode) {
+				case "head":
+					// Process the HEAD git config.
+					cmn.Debug("%s: git %d: processor %d: mode: head", funcName, i, j)
+					err := gitHead(src, configs.Gits[i].Processors[j])
+					if err != nil {
+						return err
+					}
+				case "each":
+					// Process the Each git config.
+					cmn.Debug("%s: git %d: processor %d: mode: each", funcName, i, j)
+					err := gitEach(src, configs.Gits[i], configs.Gits[i].Processors[j], stateKey(i, j, src))
+					if err != nil {
+						return err
+					}
+				case "all":
+					// Process the All git config.
+					cmn.Debug("%s: git %d: processor %d: mode: all", funcName, i, j)
+					err := gitAll(src, configs.Gits[i].Processors[j])
+					if err != nil {
+						return err
+					}
+				case "file":
+					// Process the File git config.
+					cmn.Debug("%s: git %d: processor %d: mode: file", funcName, i, j)
+					for _, member := range src.sources() {
+						err := gitFiles(member, configs.Gits[i].Processors[j])
+						if err != nil {
+							return err
+						}
+					}
+				case "tags":
+					// Process the Tags git config.
+					cmn.Debug("%s: git %d: processor %d: mode: tags", funcName, i, j)
+					for _, member := range src.sources() {
+						err := gitTags(member, configs.Gits[i].Processors[j])
+						if err != nil {
+							return err
+						}
+					}
+				case "blame":
+					// Process the Blame git config.
+					cmn.Debug("%s: git %d: processor %d: mode: blame", funcName, i, j)
+					for _, member := range src.sources() {
+						err := gitBlames(member, configs.Gits[i].Processors[j])
+						if err != nil {
+							return err
+						}
+					}
+				case "authors":
+					// Process the Authors git config.
+					cmn.Debug("%s: git %d: processor %d: mode: authors", funcName, i, j)
+					err := gitAuthors(src, configs.Gits[i].Processors[j])
+					if err != nil {
+						return err
+					}
+				case "graph":
+					// Process the Graph git config.
+					cmn.Debug("%s: git %d: processor %d: mode: graph", funcName, i, j)
+					err := gitGraph(src, configs.Gits[i].Processors[j])
+					if err != nil {
+						return err
+					}
+				case "activity":
+					// Process the Activity git config.
+					cmn.Debug("%s: git %d: processor %d: mode: activity", funcName, i, j)
+					err := gitActivity(src, configs.Gits[i].Processors[j])
+					if err != nil {
+						return err
+					}
+				case "inventory":
+					// Process the Inventory git config.
+					cmn.Debug("%s: git %d: processor %d: mode: inventory", funcName, i, j)
+					for _, member := range src.sources() {
+						err := gitInventory(member, configs.Gits[i].Processors[j])
+						if err != nil {
+							return err
+						}
+					}
+				case "owners":
+					// Process the Owners git config.
+					cmn.Debug("%s: git %d: processor %d: mode: owners", funcName, i, j)
+					for _, member := range src.sources() {
+						err := gitOwners(member, configs.Gits[i].Processors[j])
+						if err != nil {
+							return err
+						}
+					}
+				default:
+					return fmt.Errorf("invalid git processor mode; should be head/each/all/file/tags/blame/authors/graph/activity/inventory/owners")
 				}
-			default:
-				return fmt.Errorf("invalid git processor mode; should be head/each/all")
 			}
 		}
 	}
@@ -0,0 +1,136 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// referencePattern is a compiled issue or ticket reference pattern of a git handler.
+type referencePattern struct {
+	name    string
+	pattern *regexp.Regexp
+	url     *template.Template
+}
+
+// loadReferences compiles the reference patterns and URL templates of the git handler.
+func loadReferences(configs []cmn.GitReferencePattern) ([]referencePattern, error) {
+	patterns := make([]referencePattern, 0, len(configs))
+	for i := range configs {
+		pattern, err := regexp.Compile(configs[i].Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference pattern: %s: %w", configs[i].Name, err)
+		}
+		url, err := template.New(configs[i].Name).Funcs(sprig.FuncMap()).Parse(configs[i].URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference url: %s: %w", configs[i].Name, err)
+		}
+		patterns = append(patterns, referencePattern{name: configs[i].Name, pattern: pattern, url: url})
+	}
+
+	return patterns, nil
+}
+
+// referenceMatch is a reference found in a text, and its position.
+type referenceMatch struct {
+	start int
+	end   int
+	ref   cmn.GitReference
+}
+
+// findReferences finds the references in the text, ordered by position. Where matches
+// overlap, the earlier pattern wins. The key and number are the `key` and `number` named
+// groups of the pattern; the number defaults to the first group, or the whole match.
+func findReferences(patterns []referencePattern, text string) ([]referenceMatch, error) {
+	var matches []referenceMatch
+	taken := func(start, end int) bool {
+		for i := range matches {
+			if start < matches[i].end && matches[i].start < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, p := range patterns {
+		for _, loc := range p.pattern.FindAllStringSubmatchIndex(text, -1) {
+			if taken(loc[0], loc[1]) {
+				continue
+			}
+			ref := cmn.GitReference{Name: p.name, Text: text[loc[0]:loc[1]], Number: text[loc[0]:loc[1]]}
+			if len(loc) > 3 && loc[2] >= 0 {
+				ref.Number = text[loc[2]:loc[3]]
+			}
+			for i, group := range p.pattern.SubexpNames() {
+				if loc[2*i] < 0 {
+					continue
+				}
+				switch group {
+				case "key":
+					ref.Key = text[loc[2*i]:loc[2*i+1]]
+				case "number":
+					ref.Number = text[loc[2*i]:loc[2*i+1]]
+				}
+			}
+
+			var url bytes.Buffer
+			err := p.url.Execute(&url, ref)
+			if err != nil {
+				return nil, err
+			}
+			ref.URL = url.String()
+			matches = append(matches, referenceMatch{start: loc[0], end: loc[1], ref: ref})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	return matches, nil
+}
+
+// parseReferences returns the distinct references of the text, in order of appearance.
+func parseReferences(patterns []referencePattern, text string) ([]cmn.GitReference, error) {
+	matches, err := findReferences(patterns, text)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []cmn.GitReference
+	seen := map[string]bool{}
+	for i := range matches {
+		id := matches[i].ref.Name + "\x00" + matches[i].ref.Text
+		if !seen[id] {
+			seen[id] = true
+			refs = append(refs, matches[i].ref)
+		}
+	}
+
+	return refs, nil
+}
+
+// linkReferences returns the text with its references turned into Markdown links.
+func linkReferences(patterns []referencePattern, text string) (string, error) {
+	matches, err := findReferences(patterns, text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	last := 0
+	for i := range matches {
+		b.WriteString(text[last:matches[i].start])
+		fmt.Fprintf(&b, "[%s](%s)", matches[i].ref.Text, matches[i].ref.URL)
+		last = matches[i].end
+	}
+	b.WriteString(text[last:])
+
+	return b.String(), nil
+}
@@ -0,0 +1,133 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// shallowHistory is the truncated history of a shallow repository; the boundary commits,
+// whose parents are missing, are treated as root commits.
+type shallowHistory struct {
+	boundary map[plumbing.Hash]bool
+	missing  []plumbing.Hash
+}
+
+// loadShallow reads the boundary commits of a shallow repository, applying the shallow
+// policy of the git handler; `error` fails, `warn` writes a warning, and `allow` continues
+// silently. No history is returned for a complete repository.
+func loadShallow(repo *git.Repository, config cmn.Git) (*shallowHistory, error) {
+	funcName := "processors.loadShallow"
+	cmn.Debug("%s: begin", funcName)
+
+	hashes, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) == 0 {
+		cmn.Debug("%s: complete repository", funcName)
+		return nil, nil
+	}
+
+	path := config.Path
+	if path == "" {
+		path = "."
+	}
+	switch strings.ToLower(config.Shallow) {
+	case "error":
+		return nil, fmt.Errorf("shallow repository: %s; fetch the full history, or set the shallow policy to warn or allow", path)
+	case "allow":
+	default:
+		fmt.Fprintf(os.Stderr, "warning: shallow repository: %s; history is truncated at %d boundary commits\n", path, len(hashes))
+	}
+
+	history := &shallowHistory{boundary: map[plumbing.Hash]bool{}}
+	for _, hash := range hashes {
+		history.boundary[hash] = true
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil, err
+		}
+		history.missing = append(history.missing, commit.ParentHashes...)
+	}
+	cmn.Debug("%s: %d boundary commits", funcName, len(hashes))
+
+	cmn.Debug("%s: end", funcName)
+	return history, nil
+}
+
+// isBoundary reports whether the commit is a boundary commit of a shallow repository.
+func (h *shallowHistory) isBoundary(hash plumbing.Hash) bool {
+	return h != nil && h.boundary[hash]
+}
+
+// ignore returns the missing parents of the boundary commits, not to be walked.
+func (h *shallowHistory) ignore() []plumbing.Hash {
+	if h == nil {
+		return nil
+	}
+
+	return h.missing
+}
+
+// commitStats returns the file stats of the commit; empty for a boundary commit, as its
+// parent is missing.
+func commitStats(src *gitSource, commit *object.Commit) (object.FileStats, error) {
+	if src.shallow.isBoundary(commit.Hash) {
+		return object.FileStats{}, nil
+	}
+
+	return commit.Stats()
+}
+
+// boundaryObject is a boundary commit re-encoded without parents, keeping its hash.
+type boundaryObject struct {
+	plumbing.EncodedObject
+	hash plumbing.Hash
+}
+
+func (o *boundaryObject) Hash() plumbing.Hash { return o.hash }
+
+// shallowStorer is an object storer reading boundary commits without their missing
+// parents; for history walks that can't skip them, such as blame.
+type shallowStorer struct {
+	storer.EncodedObjectStorer
+	history *shallowHistory
+}
+
+func (s *shallowStorer) EncodedObject(t plumbing.ObjectType, hash plumbing.Hash) (plumbing.EncodedObject, error) {
+	obj, err := s.EncodedObjectStorer.EncodedObject(t, hash)
+	if err != nil || obj.Type() != plumbing.CommitObject || !s.history.isBoundary(hash) {
+		return obj, err
+	}
+
+	commit, err := object.DecodeCommit(s.EncodedObjectStorer, obj)
+	if err != nil {
+		return nil, err
+	}
+	commit.ParentHashes = nil
+	encoded := &plumbing.MemoryObject{}
+	err = commit.Encode(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &boundaryObject{EncodedObject: encoded, hash: hash}, nil
+}
+
+// commitObject reads the commit of the source; its history ends at the boundary commits
+// of a shallow repository.
+func commitObject(src *gitSource, hash plumbing.Hash) (*object.Commit, error) {
+	if src.shallow == nil {
+		return src.repo.CommitObject(hash)
+	}
+
+	return object.GetCommit(&shallowStorer{EncodedObjectStorer: src.repo.Storer, history: src.shallow}, hash)
+}
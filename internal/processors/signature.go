@@ -0,0 +1,277 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	pgpSignatureHeader = "-----BEGIN PGP SIGNATURE-----"
+	sshSignatureHeader = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureFooter = "-----END SSH SIGNATURE-----"
+	sshSignatureMagic  = "SSHSIG"
+)
+
+// allowedSigner is an entry of an SSH allowed signers file.
+type allowedSigner struct {
+	principals string
+	key        ssh.PublicKey
+}
+
+// keyring holds the keys trusted to sign commits and tags; armored PGP public keys, or
+// the entries of an SSH allowed signers file.
+type keyring struct {
+	pgp     openpgp.EntityList
+	signers []allowedSigner
+}
+
+// loadKeyring reads the configured keyring file; no keyring is returned for an empty path.
+func loadKeyring(path string) (*keyring, error) {
+	funcName := "processors.loadKeyring"
+	cmn.Debug("%s: begin", funcName)
+
+	if path == "" {
+		cmn.Debug("%s: no keyring", funcName)
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	kr := &keyring{}
+	if bytes.Contains(data, []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----")) {
+		kr.pgp, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid keyring: %s: %w", path, err)
+		}
+		cmn.Debug("%s: read %d PGP keys", funcName, len(kr.pgp))
+		return kr, nil
+	}
+
+	// Allowed signers; `principals [options] keytype key [comment]`.
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		principals, rest, _ := strings.Cut(line, " ")
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed signers entry: %s: %w", path, err)
+		}
+		kr.signers = append(kr.signers, allowedSigner{principals: principals, key: key})
+	}
+	cmn.Debug("%s: read %d allowed signers", funcName, len(kr.signers))
+
+	cmn.Debug("%s: end", funcName)
+	return kr, nil
+}
+
+// pgpKeyID extracts the issuer key ID of an armored PGP signature.
+func pgpKeyID(signature string) string {
+	block, err := armor.Decode(strings.NewReader(signature))
+	if err != nil {
+		return ""
+	}
+	p, err := packet.Read(block.Body)
+	if err != nil {
+		return ""
+	}
+	sig, ok := p.(*packet.Signature)
+	if !ok || sig.IssuerKeyId == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%016X", *sig.IssuerKeyId)
+}
+
+// sshString reads a length prefixed string of the SSH wire format.
+func sshString(r io.Reader) ([]byte, error) {
+	var length uint32
+	err := binary.Read(r, binary.BigEndian, &length)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]byte, length)
+	_, err = io.ReadFull(r, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// sshSignature is a parsed SSH signature; see PROTOCOL.sshsig of OpenSSH.
+type sshSignature struct {
+	key       ssh.PublicKey
+	namespace []byte
+	reserved  []byte
+	hashAlg   string
+	signature *ssh.Signature
+}
+
+// parseSSHSignature parses an armored SSH signature.
+func parseSSHSignature(armored string) (*sshSignature, error) {
+	armored = strings.TrimSpace(armored)
+	armored = strings.TrimPrefix(armored, sshSignatureHeader)
+	armored = strings.TrimSuffix(armored, sshSignatureFooter)
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(armored), ""))
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(blob)
+	magic := make([]byte, len(sshSignatureMagic))
+	var version uint32
+	if _, err = io.ReadFull(r, magic); err != nil || string(magic) != sshSignatureMagic {
+		return nil, errors.New("invalid SSH signature")
+	}
+	if err = binary.Read(r, binary.BigEndian, &version); err != nil || version != 1 {
+		return nil, errors.New("unsupported SSH signature version")
+	}
+
+	fields := make([][]byte, 5)
+	for i := range fields {
+		fields[i], err = sshString(r)
+		if err != nil {
+			return nil, errors.New("invalid SSH signature")
+		}
+	}
+
+	sig := &sshSignature{
+		namespace: fields[1],
+		reserved:  fields[2],
+		hashAlg:   string(fields[3]),
+		signature: &ssh.Signature{},
+	}
+	sig.key, err = ssh.ParsePublicKey(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	err = ssh.Unmarshal(fields[4], sig.signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// verifySSH verifies the SSH signature of the message.
+func verifySSH(sig *sshSignature, message []byte) error {
+	if string(sig.namespace) != "git" {
+		return fmt.Errorf("unexpected SSH signature namespace: %s", sig.namespace)
+	}
+
+	var h hash.Hash
+	switch sig.hashAlg {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported SSH signature hash: %s", sig.hashAlg)
+	}
+	h.Write(message)
+
+	// The signed data; the magic, then namespace, reserved, hash algorithm and message hash as strings.
+	signed := bytes.NewBufferString(sshSignatureMagic)
+	for _, field := range [][]byte{sig.namespace, sig.reserved, []byte(sig.hashAlg), h.Sum(nil)} {
+		_ = binary.Write(signed, binary.BigEndian, uint32(len(field)))
+		signed.Write(field)
+	}
+
+	return sig.key.Verify(signed.Bytes(), sig.signature)
+}
+
+// verifySignature verifies the signature of a commit or tag, given its encoding without
+// the signature, against the keyring.
+func verifySignature(kr *keyring, signature string, encode func(plumbing.EncodedObject) error) (cmn.GitSignature, error) {
+	result := cmn.GitSignature{}
+	signature = strings.TrimSpace(signature)
+	if signature == "" {
+		return result, nil
+	}
+	result.Signed = true
+
+	encoded := &plumbing.MemoryObject{}
+	err := encode(encoded)
+	if err != nil {
+		return result, err
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return result, err
+	}
+	message, err := io.ReadAll(reader)
+	if err != nil {
+		return result, err
+	}
+
+	switch {
+	case strings.HasPrefix(signature, pgpSignatureHeader):
+		result.Type = "pgp"
+		result.KeyID = pgpKeyID(signature)
+		if kr == nil || len(kr.pgp) == 0 {
+			result.Error = "no PGP keyring"
+			return result, nil
+		}
+		entity, err := openpgp.CheckArmoredDetachedSignature(kr.pgp, bytes.NewReader(message), strings.NewReader(signature), nil)
+		if err != nil {
+			result.Error = err.Error()
+			return result, nil
+		}
+		result.Valid = true
+		if identity := entity.PrimaryIdentity(); identity != nil {
+			result.Signer = identity.Name
+		}
+	case strings.HasPrefix(signature, sshSignatureHeader):
+		result.Type = "ssh"
+		sig, err := parseSSHSignature(signature)
+		if err != nil {
+			result.Error = err.Error()
+			return result, nil
+		}
+		result.KeyID = ssh.FingerprintSHA256(sig.key)
+		if kr == nil || len(kr.signers) == 0 {
+			result.Error = "no allowed signers"
+			return result, nil
+		}
+		err = verifySSH(sig, message)
+		if err != nil {
+			result.Error = err.Error()
+			return result, nil
+		}
+		for i := range kr.signers {
+			if bytes.Equal(kr.signers[i].key.Marshal(), sig.key.Marshal()) {
+				result.Valid = true
+				result.Signer = kr.signers[i].principals
+				return result, nil
+			}
+		}
+		result.Error = "key not in allowed signers"
+	default:
+		result.Type = "x509"
+		result.Error = "unsupported signature type"
+	}
+
+	return result, nil
+}
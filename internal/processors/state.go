@@ -0,0 +1,168 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// gitStateEntry is the last commit processed by a git processor for a ref.
+type gitStateEntry struct {
+	Commit string `json:"commit"`
+	Config string `json:"config"`
+}
+
+// gitState is the persisted state of incremental git processing.
+type gitState struct {
+	path    string
+	Entries map[string]gitStateEntry `json:"entries"`
+}
+
+// loadState reads the state file; no state is kept when the path is empty.
+func loadState(path string) (*gitState, error) {
+	funcName := "processors.loadState"
+	cmn.Debug("%s: begin", funcName)
+
+	if path == "" {
+		cmn.Debug("%s: no state file", funcName)
+		return nil, nil
+	}
+
+	state := &gitState{path: path, Entries: map[string]gitStateEntry{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		cmn.Debug("%s: state file not found; starting fresh: %s", funcName, path)
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state file: %s: %w", path, err)
+	}
+	if state.Entries == nil {
+		state.Entries = map[string]gitStateEntry{}
+	}
+	cmn.Debug("%s: loaded %d entries from %s", funcName, len(state.Entries), path)
+
+	cmn.Debug("%s: end", funcName)
+	return state, nil
+}
+
+// save writes the state file.
+func (s *gitState) save() error {
+	if s == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(s.path, string(data)+"\n")
+}
+
+// stateKey identifies the git processor of a git handler for a ref.
+func stateKey(handler int, processor int, src *gitSource) string {
+	return fmt.Sprintf("git %d: processor %d: %s", handler, processor, src.info.Name)
+}
+
+// memberKey returns the state key of a member source of a merged source.
+func memberKey(key string, src *gitSource) string {
+	if src.info.Repo == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s: %s", key, src.info.Repo)
+}
+
+// configHash identifies the configuration of the git processor and of its git handler,
+// such as its refs, repos, mailmap, keyring and references; a changed configuration
+// requires a full run. The other processors of the handler are not included.
+func configHash(handler cmn.Git, processor cmn.GitProcessor) string {
+	handler.Processors = nil
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%#v\n%#v", handler, processor)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// processed returns the commits already processed for the key; those reachable from the
+// recorded commit. Nil is returned for a full run; when no state is kept, nothing was
+// recorded, the configuration changed, or the history was rewritten so that the recorded
+// commit is no longer an ancestor of the hash.
+func (s *gitState) processed(src *gitSource, key string, handler cmn.Git, processor cmn.GitProcessor) (map[plumbing.Hash]bool, error) {
+	funcName := "processors.gitState.processed"
+	cmn.Debug("%s: begin", funcName)
+
+	if s == nil {
+		return nil, nil
+	}
+
+	entry, ok := s.Entries[key]
+	if !ok {
+		cmn.Debug("%s: %s: no recorded commit; full run", funcName, key)
+		return nil, nil
+	}
+	if entry.Config != configHash(handler, processor) {
+		cmn.Debug("%s: %s: configuration changed; full run", funcName, key)
+		return nil, nil
+	}
+
+	last, err := src.repo.CommitObject(plumbing.NewHash(entry.Commit))
+	if err != nil {
+		cmn.Debug("%s: %s: recorded commit not found; full run", funcName, key)
+		return nil, nil
+	}
+	head, err := src.repo.CommitObject(src.ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+	ancestor, err := last.IsAncestor(head)
+	if err == plumbing.ErrObjectNotFound {
+		cmn.Debug("%s: %s: history truncated; full run", funcName, key)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ancestor {
+		cmn.Debug("%s: %s: history rewritten; full run", funcName, key)
+		return nil, nil
+	}
+
+	// Mark the history of the recorded commit as processed.
+	seen := map[plumbing.Hash]bool{}
+	err = object.NewCommitPreorderIter(last, nil, src.shallow.ignore()).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	cmn.Debug("%s: %s: %d commits already processed", funcName, key, len(seen))
+
+	cmn.Debug("%s: end", funcName)
+	return seen, nil
+}
+
+// record notes the hash as the last commit processed for the key, and saves the state.
+func (s *gitState) record(src *gitSource, key string, handler cmn.Git, processor cmn.GitProcessor) error {
+	if s == nil {
+		return nil
+	}
+
+	s.Entries[key] = gitStateEntry{
+		Commit: src.ref.Hash().String(),
+		Config: configHash(handler, processor),
+	}
+
+	return s.save()
+}
@@ -0,0 +1,109 @@
+// Package processors provides the various functions to run processors.
+package processors
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jason-dour/hugo-preproc/internal/cmn"
+)
+
+// worktree is the status of the working tree of a repository, and the configured git user
+// used for uncommitted changes.
+type worktree struct {
+	status  cmn.GitStatus
+	changed map[string]bool
+	user    object.Signature
+}
+
+// loadWorktree reads the status of the working tree; no worktree is returned for a bare
+// repository.
+func loadWorktree(repo *git.Repository) (*worktree, error) {
+	funcName := "processors.loadWorktree"
+	cmn.Debug("%s: begin", funcName)
+
+	tree, err := repo.Worktree()
+	if err == git.ErrIsBareRepository {
+		cmn.Debug("%s: bare repository", funcName)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status, err := tree.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	wt := &worktree{
+		status:  cmn.GitStatus{Dirty: !status.IsClean()},
+		changed: map[string]bool{},
+	}
+	for path, file := range status {
+		switch {
+		case file.Worktree == git.Untracked:
+			wt.status.Untracked = append(wt.status.Untracked, path)
+		default:
+			if file.Staging != git.Unmodified {
+				wt.status.Staged = append(wt.status.Staged, path)
+			}
+			if file.Worktree != git.Unmodified {
+				wt.status.Modified = append(wt.status.Modified, path)
+			}
+		}
+		wt.changed[path] = true
+	}
+	sort.Strings(wt.status.Modified)
+	sort.Strings(wt.status.Staged)
+	sort.Strings(wt.status.Untracked)
+	cmn.Debug("%s: %d modified, %d staged, %d untracked", funcName,
+		len(wt.status.Modified), len(wt.status.Staged), len(wt.status.Untracked))
+
+	// The git user, as configured for the repository or globally.
+	cfg, err := repo.ConfigScoped(config.GlobalScope)
+	if err != nil {
+		return nil, err
+	}
+	wt.user = object.Signature{Name: cfg.User.Name, Email: cfg.User.Email}
+
+	cmn.Debug("%s: end", funcName)
+	return wt, nil
+}
+
+// workingCopyEntry builds the synthetic log entry of an uncommitted file; dated now and
+// authored by the configured git user.
+func workingCopyEntry(src *gitSource) cmn.GitLogEntry {
+	user := src.worktree.user
+	user.When = time.Now()
+	message := "Working copy\n"
+
+	return cmn.GitLogEntry{
+		Commit: &object.Commit{
+			Hash:      plumbing.ZeroHash,
+			Author:    user,
+			Committer: user,
+			Message:   message,
+		},
+		Author:      src.mailmap.resolve(user.Name, user.Email),
+		Committer:   src.mailmap.resolve(user.Name, user.Email),
+		Stats:       object.FileStats{},
+		Parsed:      parseMessage(message),
+		Ref:         src.info,
+		WorkingCopy: true,
+	}
+}
+
+// workingCopy reports whether any processor of the git handler uses the working copy.
+func workingCopy(config cmn.Git) bool {
+	for i := range config.Processors {
+		if config.Processors[i].WorkingCopy {
+			return true
+		}
+	}
+
+	return false
+}